@@ -1,7 +1,10 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"log"
+	"sort"
 
 	"github.com/mdemidenko/monitoring-platform/config"
 	"github.com/mdemidenko/monitoring-platform/internal/repository"
@@ -9,11 +12,34 @@ import (
 )
 
 func main() {
-	cfg := config.FileLoadConfig()
+	flags := config.RegisterFlags(flag.CommandLine)
+	monitorFlags := config.RegisterFileFlags(flag.CommandLine)
+	flag.Parse()
+	cfg, err := config.FileLoadConfig(flags.Config, monitorFlags)
+	if err != nil {
+		log.Fatalf("failed to load monitor config: %v", err)
+	}
 
 	// Инициализация зависимостей
-	repo := repository.NewRepository(cfg.InputFile, cfg.OutputFile)
-	svc := monitor.New(repo)
+	opts := []repository.Option{
+		repository.WithLenientParsing(cfg.Lenient),
+		repository.WithFieldMapping(cfg.FieldMapping),
+		repository.WithLockWait(cfg.LockWait),
+		repository.WithKeepOnEmpty(cfg.KeepOnEmpty),
+		repository.WithSourceTimeout(cfg.InputTimeout),
+		repository.WithSinkTimeout(cfg.OutputTimeout),
+	}
+	if cfg.Rotate {
+		opts = append(opts, repository.WithRotation(cfg.KeepFiles))
+	}
+	for _, url := range cfg.OutputURLs {
+		opts = append(opts, repository.WithSinks(repository.NewHTTPSink(url, cfg.OutputBatchSize)))
+	}
+	repo := repository.NewRepository(cfg.InputFile, cfg.OutputFile, opts...)
+	svc := monitor.New(repo,
+		monitor.WithDateLayouts(cfg.DateLayouts),
+		monitor.WithBusinessLine(cfg.BusinessLine),
+	)
 
 
 	// Вызов бизнес-логики
@@ -23,15 +49,50 @@ func main() {
 		return
 	}
 
+	// Проверка результатов перед сохранением
+	validationMode, err := monitor.ParseValidationMode(cfg.Validation)
+	if err != nil {
+		fmt.Println("Ошибка конфигурации:", err)
+		return
+	}
+	results, rejected, err := monitor.ValidateResults(results, validationMode)
+	if err != nil {
+		fmt.Println("Ошибка проверки результатов:", err)
+		return
+	}
+
 	// Сохранение результата
 	if err := repo.SaveResults(results); err != nil {
 		fmt.Println("Ошибка сохранения:", err)
 		return
 	}
 
+	// Сводка по тенантам
+	summary := monitor.SummarizeByTenant(results)
+	if cfg.SummaryFile != "" {
+		if err := repository.SaveSummary(cfg.SummaryFile, summary); err != nil {
+			fmt.Println("Ошибка сохранения сводки:", err)
+			return
+		}
+	}
+
 	// Вывод
 	fmt.Printf("Найдено подходящих сервисов: %d\n", len(results))
 	for i, svc := range results {
 		fmt.Printf("  %d. ID: %d, Name: %s, Tenant: %s\n", i+1, svc.ID, svc.Name, svc.Tenant)
 	}
+	if len(summary.Tenants) > 0 {
+		fmt.Println("По тенантам:")
+		tenants := make([]string, 0, len(summary.Tenants))
+		for tenant := range summary.Tenants {
+			tenants = append(tenants, tenant)
+		}
+		sort.Strings(tenants)
+		for _, tenant := range tenants {
+			fmt.Printf("  %s: %d\n", tenant, summary.Tenants[tenant].Count)
+		}
+	}
+	if rejected > 0 {
+		fmt.Printf("Отклонено некорректных результатов: %d\n", rejected)
+	}
 }
\ No newline at end of file
@@ -2,51 +2,112 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
 	"log"
 	"os"
 	"os/signal"
+	"slices"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/mdemidenko/monitoring-platform/config"
+	"github.com/mdemidenko/monitoring-platform/internal/health"
 	"github.com/mdemidenko/monitoring-platform/internal/logger"
 	"github.com/mdemidenko/monitoring-platform/internal/models"
 	"github.com/mdemidenko/monitoring-platform/internal/notifier"
 	"github.com/mdemidenko/monitoring-platform/internal/repository"
+	"github.com/mdemidenko/monitoring-platform/internal/version"
 )
 
+// healthCheckTimeout ограничивает время проверки доступности бота при старте
+const healthCheckTimeout = 5 * time.Second
+
 func main() {
 	// Создаем контекст с возможностью отмены
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
 	// Загружаем конфигурацию
-	cfg, err := config.LoadConfig("")
+	flags := config.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+	cfg, err := config.LoadConfig(flags.Config)
 	if err != nil {
 		log.Fatal(err)
 	}
 
+	log.Printf("🚀 %s", version.Get(cfg.App.Name, cfg.App.Version))
+
+	if cfg.Telegram.Debug {
+		if data, err := json.Marshal(cfg.Redacted()); err == nil {
+			log.Printf("Effective configuration: %s", data)
+		}
+	}
+
 	// Создаем репозиторий для слайсов
 	storage := repository.NewMemoryStorage()
 
+	// Фоновая очистка хранилища, чтобы долгоживущий процесс не копил записи
+	retention := repository.Retention{
+		MaxItems: cfg.Storage.MaxItems,
+		MaxAge:   cfg.Storage.MaxAge.Duration(),
+	}
+	if retention.Enabled() && cfg.Storage.CompactInterval > 0 {
+		go storage.RunCompactor(ctx, cfg.Storage.CompactInterval.Duration(), retention)
+	}
+
 	// Создаем и запускаем логгер хранилища с контекстом
-	storageLogger := logger.NewStorageLogger(storage, 200*time.Millisecond)
+	var loggerOpts []logger.Option
+	if path := cfg.Logging.StorageFile; path != "" {
+		eventFile, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			log.Fatalf("failed to open storage log file: %v", err)
+		}
+		defer eventFile.Close()
+		loggerOpts = append(loggerOpts, logger.WithEventWriter(eventFile))
+	}
+	storageLogger := logger.NewStorageLogger(storage, 200*time.Millisecond, loggerOpts...)
 	storageLogger.Start(ctx)
 
 	// Создаем сервис
 	telegramService := notifier.NewTelegramService(cfg, storage)
 
-	// Проверяем здоровье бота
-	if err := telegramService.HealthCheck(); err != nil {
-		log.Fatal(err)
+	// Загружаем шаблоны сообщений
+	if cfg.Telegram.TemplatesDir != "" {
+		if err := telegramService.LoadTemplates(cfg.Telegram.TemplatesDir); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// Регистрируем проверки подсистем и проверяем их здоровье
+	healthRegistry := health.NewRegistry()
+	healthRegistry.Register(health.NewChecker("telegram", telegramService.HealthCheck))
+	healthRegistry.Register(health.NewChecker("storage", func(context.Context) error {
+		// In-memory хранилище всегда доступно; проверка для персистентных хранилищ
+		return nil
+	}))
+	healthRegistry.Register(health.NewChecker("storage_logger", storageLogger.Check))
+
+	healthCtx, healthCancel := context.WithTimeout(ctx, healthCheckTimeout)
+	report := healthRegistry.CheckAll(healthCtx)
+	healthCancel()
+	for name, status := range report.Components {
+		if status.Status != health.StatusOK {
+			log.Printf("❌ Подсистема %s: %s", name, status.Error)
+		}
+	}
+	if !report.Healthy {
+		log.Fatal("health check failed")
 	}
 
 	// Предопределяем уведомления
 	notifications := []*models.Notification{
-		{ChatID: cfg.Telegram.ChatID, Text: "🔔 Проверка системы!"},
-		{ChatID: cfg.Telegram.ChatID, Text: "✅ Проверка прошла успешно"},
-		{ChatID: cfg.Telegram.ChatID, Text: "⚠️ Предупреждение системы"},
-		{ChatID: cfg.Telegram.ChatID, Text: "📊 Статистика работы"},
+		models.NewNotification(cfg.Telegram.ChatID, "🔔 Проверка системы!"),
+		models.NewNotification(cfg.Telegram.ChatID, "✅ Проверка прошла успешно"),
+		models.NewNotification(cfg.Telegram.ChatID, "⚠️ Предупреждение системы"),
+		models.NewNotification(cfg.Telegram.ChatID, "📊 Статистика работы"),
 	}
 
 	log.Printf("Начинаем обработку %d уведомлений с интервалами...", len(notifications))
@@ -55,10 +116,48 @@ func main() {
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
+	// SIGHUP перечитывает конфигурацию без перезапуска
+	reloader := newConfigReloader(telegramService)
+	hupChan := make(chan os.Signal, 1)
+	signal.Notify(hupChan, syscall.SIGHUP)
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hupChan:
+				if err := reloader.reload(); err != nil {
+					log.Printf("⚠️  Не удалось перечитать конфигурацию: %v", err)
+				}
+			}
+		}
+	}()
+
+	// Изменения файла конфигурации применяются так же, как по SIGHUP
+	if cfg.App.WatchConfig && cfg.Path != "" {
+		go func() {
+			err := config.Watch(ctx, cfg.Path, config.DefaultWatchDebounce, func() {
+				if err := reloader.reload(); err != nil {
+					log.Printf("⚠️  Не удалось перечитать конфигурацию: %v", err)
+				}
+			})
+			if err != nil {
+				log.Printf("⚠️  Отслеживание файла конфигурации недоступно: %v", err)
+			}
+		}()
+		log.Printf("👀 Отслеживаем изменения %s", cfg.Path)
+	}
+
+	// Отложенные в тихие часы уведомления отправляются после их окончания
+	go telegramService.RunQuietHours(ctx)
+
 	// Запускаем обработку уведомлений в отдельной горутине
+	workCtx, cancelWork := context.WithCancel(ctx)
+	defer cancelWork()
+
 	results := make(chan notifier.ProcessResult, 1)
 	go func() {
-		result := telegramService.ProcessWithIntervals(ctx, notifications, 2*time.Second, 2)
+		result := telegramService.ProcessWithIntervals(workCtx, notifications, 2*time.Second, 2)
 		results <- result
 	}()
 
@@ -66,32 +165,126 @@ func main() {
 	select {
 	case <-sigChan:
 		log.Println("🚨 Получен сигнал завершения, начинаем graceful shutdown...")
-		cancel()
-		
-		// Даем время на graceful shutdown
-		select {
-		case result := <-results:
-			printResults(result)
-		case <-time.After(5 * time.Second):
-			log.Println("⚠️  Таймаут graceful shutdown, принудительное завершение")
-		}
+		cancelWork()
+	case result := <-results:
+		printResults(result)
+	}
+
+	shutdown(time.Duration(cfg.App.ShutdownTimeout)*time.Second, telegramService, storageLogger)
+
+	// Итоги прерванной обработки, если она успела завершиться
+	select {
 	case result := <-results:
 		printResults(result)
-		log.Println("🔄 Завершаем логгер...")
-		cancel()
-		time.Sleep(300 * time.Millisecond)
+	default:
 	}
 
 	// Выводим статистику хранилища
 	printStorageStats(storage)
+	printLifetimeStats(telegramService.Stats())
+	if deferred := telegramService.DeferredCount(); deferred > 0 {
+		log.Printf("🌙 Не отправлено из-за тихих часов: %d", deferred)
+	}
 	log.Println("👋 Приложение завершено")
 }
 
+// configReloader перечитывает файл конфигурации и применяет изменяемые на лету поля
+type configReloader struct {
+	mu  sync.Mutex
+	svc *notifier.TelegramService
+	// loaded конфигурация, прочитанная из файла в прошлый раз. Предупреждения
+	// о полях, требующих перезапуска, выводятся только для новых изменений.
+	loaded *config.Config
+}
+
+func newConfigReloader(svc *notifier.TelegramService) *configReloader {
+	return &configReloader{svc: svc, loaded: svc.Config()}
+}
+
+// reload перечитывает файл конфигурации; вызывается по SIGHUP и при изменении файла
+func (r *configReloader) reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current := r.svc.Config()
+	if current.Path == "" {
+		return fmt.Errorf("configuration was loaded from environment, nothing to reload")
+	}
+
+	next, err := config.LoadConfig(current.Path)
+	if err != nil {
+		return err
+	}
+
+	merged, ignored := config.MergeReloadable(current, next)
+	_, changed := config.MergeReloadable(r.loaded, next)
+	for _, field := range ignored {
+		if slices.Contains(changed, field) {
+			log.Printf("⚠️  Поле %s нельзя изменить без перезапуска, изменение проигнорировано", field)
+		}
+	}
+
+	r.svc.UpdateConfig(merged)
+	r.loaded = next
+	log.Printf("🔄 Конфигурация перечитана из %s", current.Path)
+
+	return nil
+}
+
+// shutdown останавливает компоненты по порядку: сначала дожидается
+// обработки уведомлений, затем останавливает логгер хранилища.
+// Все шаги укладываются в общий timeout.
+func shutdown(timeout time.Duration, svc *notifier.TelegramService, storageLogger *logger.StorageLogger) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	log.Println("🔄 Завершаем обработку уведомлений...")
+	if err := svc.Shutdown(ctx); err != nil {
+		log.Printf("⚠️  Таймаут graceful shutdown: %v", err)
+	}
+
+	log.Println("🔄 Завершаем логгер...")
+	if err := storageLogger.Stop(ctx); err != nil {
+		log.Printf("⚠️  Таймаут graceful shutdown: %v", err)
+	}
+}
+
 // printResults выводит итоги обработки
 func printResults(result notifier.ProcessResult) {
 	log.Printf("\n=== ИТОГИ ОБРАБОТКИ ===")
 	log.Printf("Успешно отправлено: %d", result.SuccessCount)
 	log.Printf("Ошибок: %d", result.ErrorCount)
+	categories := make([]notifier.ErrorCategory, 0, len(result.ErrorCategories))
+	for category := range result.ErrorCategories {
+		categories = append(categories, category)
+	}
+	slices.Sort(categories)
+	for _, category := range categories {
+		log.Printf("  %s: %d (повторять: %t)", category, result.ErrorCategories[category], category.Retryable())
+	}
+	if result.SuppressedCount > 0 {
+		log.Printf("Подавлено повторов: %d", result.SuppressedCount)
+	}
+	if result.QueuedCount > 0 {
+		log.Printf("Поставлено в очередь (пауза): %d", result.QueuedCount)
+	}
+	if result.DeferredCount > 0 {
+		log.Printf("Отложено до конца тихих часов: %d", result.DeferredCount)
+	}
+	log.Printf("Длительность: %v (задержка: средняя %v, мин %v, макс %v)",
+		result.TotalDuration, result.AvgLatency, result.MinLatency, result.MaxLatency)
+	for _, messageResult := range result.Results {
+		if !messageResult.Success {
+			log.Printf("  #%d (chat %s): %s", messageResult.Index+1, messageResult.ChatID, messageResult.Error)
+		}
+	}
+}
+
+// printLifetimeStats выводит суммарную статистику сервиса за все пакеты
+func printLifetimeStats(stats notifier.Stats) {
+	log.Printf("\n=== СТАТИСТИКА СЕРВИСА ===")
+	log.Printf("Пакетов: %d, отправлено: %d, ошибок: %d, подавлено повторов: %d",
+		stats.Batches, stats.Sent, stats.Failed, stats.Suppressed)
 }
 
 // printStorageStats выводит статистику хранилища
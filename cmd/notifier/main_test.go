@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mdemidenko/monitoring-platform/config"
+	"github.com/mdemidenko/monitoring-platform/internal/notifier"
+	"github.com/mdemidenko/monitoring-platform/internal/repository"
+)
+
+const baseConfig = `
+telegram:
+  bot_token: test-token
+  chat_id: "100"
+`
+
+func TestConfigReloaderWarnsOnce(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yml")
+	writeConfig := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeConfig(baseConfig)
+
+	cfg, err := config.LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := notifier.NewTelegramService(cfg, repository.NewMemoryStorage())
+	reloader := newConfigReloader(svc)
+
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	steps := []struct {
+		name     string
+		content  string
+		wantWarn int
+	}{
+		{name: "restart-only change", content: baseConfig + "  max_workers: 3\n", wantWarn: 1},
+		{name: "same file again", content: baseConfig + "  max_workers: 3\n", wantWarn: 0},
+		{name: "reloadable change only", content: baseConfig + "  max_workers: 3\n  message_footer: footer\n", wantWarn: 0},
+		{name: "new value of the same field", content: baseConfig + "  max_workers: 4\n  message_footer: footer\n", wantWarn: 1},
+		{name: "back to the running value", content: baseConfig + "  message_footer: footer\n", wantWarn: 0},
+	}
+
+	for _, step := range steps {
+		logs.Reset()
+		writeConfig(step.content)
+		if err := reloader.reload(); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+
+		if got := strings.Count(logs.String(), "telegram.max_workers"); got != step.wantWarn {
+			t.Errorf("%s: %d warnings, want %d; log:\n%s", step.name, got, step.wantWarn, logs.String())
+		}
+		// Поле, требующее перезапуска, не применяется
+		if svc.Config().Telegram.MaxWorkers != cfg.Telegram.MaxWorkers {
+			t.Errorf("%s: max_workers applied without restart", step.name)
+		}
+	}
+
+	if svc.Config().Telegram.MessageFooter != "footer" {
+		t.Errorf("reloadable field not applied: footer = %q", svc.Config().Telegram.MessageFooter)
+	}
+}
+
+func TestConfigWatcherReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yml")
+	if err := os.WriteFile(path, []byte(baseConfig), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := notifier.NewTelegramService(cfg, repository.NewMemoryStorage())
+	reloader := newConfigReloader(svc)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	reloads := make(chan error, 10)
+	go config.Watch(ctx, path, 50*time.Millisecond, func() { reloads <- reloader.reload() })
+	// Даем наблюдателю подписаться на каталог
+	time.Sleep(50 * time.Millisecond)
+
+	waitReload := func(content string) error {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case err := <-reloads:
+			return err
+		case <-time.After(2 * time.Second):
+			t.Fatal("config change not picked up")
+			return nil
+		}
+	}
+
+	if err := waitReload(baseConfig + "  message_footer: v2\n"); err != nil {
+		t.Fatalf("valid config rejected: %v", err)
+	}
+	if footer := svc.Config().Telegram.MessageFooter; footer != "v2" {
+		t.Fatalf("footer = %q after a valid change, want v2", footer)
+	}
+
+	// Некорректный файл отклоняется, действующая конфигурация сохраняется
+	if err := waitReload(baseConfig + "  message_footer: v3\n  default_parse_mode: Bogus\n"); err == nil {
+		t.Fatal("invalid config applied")
+	}
+	if footer := svc.Config().Telegram.MessageFooter; footer != "v2" {
+		t.Errorf("footer = %q after an invalid change, want the previous v2", footer)
+	}
+}
@@ -4,55 +4,230 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"net/url"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
 
+// FileConfig конфигурация monitor. Задается секцией monitor в YAML файле
+// конфигурации, переменными окружения MONITOR_* и флагами командной строки.
 type FileConfig struct {
-	InputFile  string
-	OutputFile string
+	// InputFile путь к файлу или http(s) URL со списком сервисов
+	InputFile  string `yaml:"input"`
+	OutputFile string `yaml:"output"`
+	// OutputURLs http(s) адреса, на которые результаты дополнительно отправляются POST запросом
+	OutputURLs []string `yaml:"output_urls"`
+	// OutputBatchSize число результатов в одном POST запросе (0 — по умолчанию)
+	OutputBatchSize int `yaml:"output_batch_size"`
+	// OutputTimeout ограничение времени записи результатов в файл и по OutputURLs (0 — без ограничения)
+	OutputTimeout time.Duration `yaml:"output_timeout"`
+	// InputTimeout ограничение времени чтения входных данных (0 — без ограничения)
+	InputTimeout time.Duration `yaml:"input_timeout"`
+	// Lenient пропускает некорректные записи во входном файле вместо ошибки
+	Lenient bool `yaml:"lenient"`
+	// Rotate записывает результаты в файлы с меткой времени вместо перезаписи
+	Rotate bool `yaml:"rotate"`
+	// KeepFiles сколько последних файлов хранить при ротации (0 — все)
+	KeepFiles int `yaml:"keep"`
+	// Validation режим проверки результатов перед записью: "", "drop" или "fail"
+	Validation string `yaml:"validate"`
+	// FieldMapping соответствие ключей входного JSON полям сервиса
+	FieldMapping map[string]string `yaml:"field_mapping"`
+	// DateLayouts форматы DeprecatedDate во входном файле (пусто — форматы по умолчанию)
+	DateLayouts []string `yaml:"date_layouts"`
+	// LockWait сколько ждать, пока другой запуск освободит файл результатов (0 — не ждать)
+	LockWait time.Duration `yaml:"lock_wait"`
+	// KeepOnEmpty не перезаписывать файл результатов, если ничего не найдено
+	KeepOnEmpty bool `yaml:"keep_on_empty"`
+	// SummaryFile файл для сводки результатов по тенантам (пусто — не записывать)
+	SummaryFile string `yaml:"summary_file"`
+	// BusinessLine бизнес-линия отбираемых сервисов (пусто — значение по умолчанию monitor)
+	BusinessLine string `yaml:"business_line"`
 }
 
-func FileLoadConfig() FileConfig {
-	return FileConfig{
-		InputFile:  "services.json",
-		OutputFile: "filtered_services.json",
+// FileLoadConfig собирает конфигурацию monitor. configPath — значение флага
+// -config, flags — флаги monitor, уже разобранные вызывающим бинарником.
+// Приоритет источников: флаги > переменные окружения > файл конфигурации >
+// значения по умолчанию.
+func FileLoadConfig(configPath string, flags *FileFlags) (FileConfig, error) {
+	cfg := DefaultFileConfig()
+
+	path, err := findConfigFile(configPath)
+	if err != nil {
+		return FileConfig{}, err
+	}
+	if path != "" {
+		if err := cfg.loadYAML(path); err != nil {
+			return FileConfig{}, err
+		}
+	}
+
+	if err := cfg.overrideFromEnv(); err != nil {
+		return FileConfig{}, err
+	}
+
+	if err := flags.applyTo(&cfg); err != nil {
+		return FileConfig{}, err
+	}
+
+	return cfg, nil
+}
+
+// FileLoadConfigArgs собирает конфигурацию monitor из переданных аргументов
+// командной строки (без имени программы)
+func FileLoadConfigArgs(args []string) (FileConfig, error) {
+	fs := flag.NewFlagSet("monitor", flag.ContinueOnError)
+	common := RegisterFlags(fs)
+	flags := RegisterFileFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return FileConfig{}, err
 	}
+	return FileLoadConfig(common.Config, flags)
 }
 
+// parseFieldMapping разбирает список пар "источник=поле" через запятую
+func parseFieldMapping(value string) (map[string]string, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	mapping := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		source, target, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || source == "" || target == "" {
+			return nil, fmt.Errorf("expected source=field, got %q", pair)
+		}
+		mapping[source] = target
+	}
+
+	return mapping, nil
+}
+
+// DefaultTelegramAPIBaseURL адрес Telegram Bot API по умолчанию
+const DefaultTelegramAPIBaseURL = "https://api.telegram.org"
+
 type TelegramConfig struct {
+	BotToken   string `yaml:"bot_token" json:"bot_token"`
+	ChatID     string `yaml:"chat_id" json:"chat_id"`
+	Debug      bool   `yaml:"debug" json:"debug"`
+	APIBaseURL string `yaml:"api_base_url" json:"api_base_url"`
+
+	// ForceChatID чат, в который уходят все сообщения вместо запрошенного
+	// (например, тестовый чат в staging); пусто — без перенаправления
+	ForceChatID string `yaml:"force_chat_id" json:"force_chat_id,omitempty"`
+
+	// BotTokenFile файл с токеном бота вместо bot_token (например, смонтированный секрет)
+	BotTokenFile string `yaml:"bot_token_file" json:"bot_token_file"`
+
+	// Timeout таймаут запроса к Telegram: "10s", "500ms" или число секунд
+	Timeout Duration `yaml:"timeout" json:"timeout"`
+
+	// Оформление исходящих сообщений
+	DefaultParseMode string `yaml:"default_parse_mode" json:"default_parse_mode"`
+	MessageFooter    string `yaml:"message_footer" json:"message_footer"`
+	// TemplatesDir каталог с шаблонами сообщений (*.tmpl)
+	TemplatesDir string `yaml:"templates_dir" json:"templates_dir"`
+
+	// Profiles именованные боты/чаты, через которые можно отправлять уведомления
+	Profiles map[string]TelegramProfile `yaml:"profiles" json:"profiles"`
+
+	// Настройки пула соединений HTTP клиента
+	MaxIdleConns        int `yaml:"max_idle_conns" json:"max_idle_conns"`
+	MaxIdleConnsPerHost int `yaml:"max_idle_conns_per_host" json:"max_idle_conns_per_host"`
+	IdleConnTimeout     int `yaml:"idle_conn_timeout" json:"idle_conn_timeout"`
+
+	// Настройки circuit breaker (cooldown в секундах)
+	BreakerThreshold   int `yaml:"breaker_threshold" json:"breaker_threshold"`
+	BreakerCooldown    int `yaml:"breaker_cooldown" json:"breaker_cooldown"`
+	BreakerMaxCooldown int `yaml:"breaker_max_cooldown" json:"breaker_max_cooldown"`
+
+	// DedupWindow окно в секундах, в течение которого повторное уведомление
+	// с тем же chat_id и текстом не отправляется (0 — без подавления)
+	DedupWindow int `yaml:"dedup_window" json:"dedup_window"`
+
+	// DeadLetterFile JSONL файл, куда дописываются уведомления, которые
+	// не удалось отправить (пусто — не сохранять)
+	DeadLetterFile string `yaml:"dead_letter_file" json:"dead_letter_file"`
+
+	// QuietHours период тишины для некритичных уведомлений (не задан — выключен)
+	QuietHours *QuietHoursConfig `yaml:"quiet_hours" json:"quiet_hours,omitempty"`
+
+	// MaxWorkers верхняя граница числа worker'ов пакетной отправки
+	MaxWorkers int `yaml:"max_workers" json:"max_workers"`
+
+	// MaxResponseSize максимальный размер ответа Bot API в байтах
+	MaxResponseSize int `yaml:"max_response_size" json:"max_response_size"`
+
+	// Кэш метаданных чатов getChat (TTL в секундах)
+	ChatCacheSize int `yaml:"chat_cache_size" json:"chat_cache_size"`
+	ChatCacheTTL  int `yaml:"chat_cache_ttl" json:"chat_cache_ttl"`
+}
+
+// TelegramProfile отдельный бот и чат для именованного профиля.
+// Если ChatID не задан, используется telegram.chat_id.
+type TelegramProfile struct {
 	BotToken string `yaml:"bot_token" json:"bot_token"`
 	ChatID   string `yaml:"chat_id" json:"chat_id"`
-	Timeout  int    `yaml:"timeout" json:"timeout"`
-	Debug    bool   `yaml:"debug" json:"debug"`
+	// BotTokenFile файл с токеном бота вместо bot_token
+	BotTokenFile string `yaml:"bot_token_file" json:"bot_token_file"`
 }
 
 type AppConfig struct {
 	Name        string `yaml:"name" json:"name"`
 	Version     string `yaml:"version" json:"version"`
 	Environment string `yaml:"environment" json:"environment"`
+	// ShutdownTimeout общий таймаут graceful shutdown в секундах
+	ShutdownTimeout int `yaml:"shutdown_timeout" json:"shutdown_timeout"`
+	// WatchConfig перечитывает изменяемые на лету поля при изменении файла конфигурации
+	WatchConfig bool `yaml:"watch_config" json:"watch_config"`
 }
 
 type LoggingConfig struct {
 	Level  string `yaml:"level" json:"level"`
 	Format string `yaml:"format" json:"format"`
+	// StorageFile файл, в который дописываются изменения хранилища (JSON lines)
+	StorageFile string `yaml:"storage_file" json:"storage_file,omitempty"`
+}
+
+// StorageConfig ограничивает объем хранилища уведомлений в памяти
+type StorageConfig struct {
+	// MaxItems сколько последних сущностей каждого типа хранить (0 — без ограничения)
+	MaxItems int `yaml:"max_items" json:"max_items"`
+	// MaxAge сколько хранить сущность после сохранения (0 — без ограничения)
+	MaxAge Duration `yaml:"max_age" json:"max_age"`
+	// CompactInterval период фоновой очистки хранилища (0 — фоновая очистка выключена)
+	CompactInterval Duration `yaml:"compact_interval" json:"compact_interval"`
 }
 
 type Config struct {
 	Telegram TelegramConfig `yaml:"telegram" json:"telegram"`
 	App      AppConfig      `yaml:"app" json:"app"`
 	Logging  LoggingConfig  `yaml:"logging" json:"logging"`
+	Storage  StorageConfig  `yaml:"storage" json:"storage"`
+
+	// Path путь к файлу, из которого загружена конфигурация
+	Path string `yaml:"-" json:"-"`
 }
 
-// LoadConfig загружает конфигурацию из YAML файла
+// LoadConfig загружает конфигурацию из YAML файла. Если путь не указан
+// и файл не найден в стандартных местах, конфигурация собирается из
+// значений по умолчанию и environment variables.
 func LoadConfig(configPath string) (*Config, error) {
-	// Если путь не указан, ищем файл в стандартных местах
+	// Если путь не указан, берем CONFIG_PATH или ищем файл в стандартных местах
 	if configPath == "" {
-		configPath = findConfigFile()
+		var err error
+		configPath, err = findConfigFile("")
+		if err != nil {
+			return nil, err
+		}
 		if configPath == "" {
-			return nil, fmt.Errorf("config file not found in standard locations")
+			return loadConfigFromEnv()
 		}
 	}
 
@@ -64,24 +239,66 @@ func LoadConfig(configPath string) (*Config, error) {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
 
-	// Парсим YAML
-	var config Config
-	if err := yaml.Unmarshal(data, &config); err != nil {
+	// Парсим YAML поверх значений по умолчанию, чтобы отсутствующие
+	// в файле поля не обнулялись
+	config := DefaultConfig()
+	if err := yaml.Unmarshal(data, config); err != nil {
 		return nil, fmt.Errorf("failed to parse YAML config: %w", err)
 	}
 
+	// Переопределяем из environment variables
+	if err := config.overrideFromEnv(); err != nil {
+		return nil, err
+	}
+	if err := config.loadSecretFiles(); err != nil {
+		return nil, err
+	}
+	config.Path = configPath
+
 	// Валидация обязательных полей
 	if err := config.Validate(); err != nil {
 		return nil, fmt.Errorf("config validation failed: %w", err)
 	}
 
-	// Переопределяем из environment variables
-	config.overrideFromEnv()
+	log.Printf("Configuration loaded successfully for app: %s v%s",
+		config.App.Name, config.App.Version)
+
+	return config, nil
+}
+
+// loadConfigFromEnv собирает конфигурацию без файла: значения по умолчанию,
+// переопределенные environment variables
+func loadConfigFromEnv() (*Config, error) {
+	log.Printf("Config file not found, loading configuration from environment")
+
+	config := DefaultConfig()
+	if err := config.overrideFromEnv(); err != nil {
+		return nil, err
+	}
+	if err := config.loadSecretFiles(); err != nil {
+		return nil, err
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("config validation failed: %w", err)
+	}
 
 	log.Printf("Configuration loaded successfully for app: %s v%s",
 		config.App.Name, config.App.Version)
 
-	return &config, nil
+	return config, nil
+}
+
+// LoadConfigArgs загружает конфигурацию notifier с флагами из переданных
+// аргументов командной строки (без имени программы). Флаги monitor notifier
+// не принимает.
+func LoadConfigArgs(args []string) (*Config, error) {
+	fs := flag.NewFlagSet("notifier", flag.ContinueOnError)
+	flags := RegisterFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	return LoadConfig(flags.Config)
 }
 
 // LoadConfigWithDefaults загружает конфиг или использует значения по умолчанию
@@ -98,13 +315,25 @@ func LoadConfigWithDefaults(configPath string) *Config {
 func DefaultConfig() *Config {
 	return &Config{
 		Telegram: TelegramConfig{
-			Timeout: 500,
-			Debug:   false,
+			Timeout:             Duration(10 * time.Second),
+			Debug:               false,
+			APIBaseURL:          DefaultTelegramAPIBaseURL,
+			MaxIdleConns:        100,
+			MaxIdleConnsPerHost: 10,
+			IdleConnTimeout:     90,
+			BreakerThreshold:    5,
+			BreakerCooldown:     30,
+			BreakerMaxCooldown:  300,
+			MaxWorkers:          10,
+			MaxResponseSize:     4 << 20,
+			ChatCacheSize:       256,
+			ChatCacheTTL:        300,
 		},
 		App: AppConfig{
-			Name:        "telegram-bot",
-			Version:     "1.0.0",
-			Environment: "development",
+			Name:            "telegram-bot",
+			Version:         "1.0.0",
+			Environment:     "development",
+			ShutdownTimeout: 5,
 		},
 		Logging: LoggingConfig{
 			Level:  "info",
@@ -124,6 +353,60 @@ func (c *Config) Validate() error {
 	if c.Telegram.Timeout <= 0 {
 		return fmt.Errorf("telegram.timeout must be positive")
 	}
+	if c.Telegram.APIBaseURL != "" {
+		u, err := url.Parse(c.Telegram.APIBaseURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("telegram.api_base_url must be an absolute http(s) URL: %q", c.Telegram.APIBaseURL)
+		}
+	}
+	for name, profile := range c.Telegram.Profiles {
+		if name == "" {
+			return fmt.Errorf("telegram.profiles: profile name must not be empty")
+		}
+		if profile.BotToken == "" {
+			return fmt.Errorf("telegram.profiles.%s.bot_token is required", name)
+		}
+	}
+
+	validParseModes := map[string]bool{
+		"":           true,
+		"HTML":       true,
+		"Markdown":   true,
+		"MarkdownV2": true,
+	}
+	if !validParseModes[c.Telegram.DefaultParseMode] {
+		return fmt.Errorf("invalid telegram.default_parse_mode: %s", c.Telegram.DefaultParseMode)
+	}
+	if c.Telegram.MaxIdleConns < 0 || c.Telegram.MaxIdleConnsPerHost < 0 || c.Telegram.IdleConnTimeout < 0 {
+		return fmt.Errorf("telegram connection pool settings must not be negative")
+	}
+	if c.Telegram.BreakerThreshold < 0 || c.Telegram.BreakerCooldown < 0 || c.Telegram.BreakerMaxCooldown < 0 {
+		return fmt.Errorf("telegram circuit breaker settings must not be negative")
+	}
+	if c.Telegram.DedupWindow < 0 {
+		return fmt.Errorf("telegram.dedup_window must not be negative")
+	}
+	if c.Telegram.QuietHours != nil {
+		if err := c.Telegram.QuietHours.Validate(); err != nil {
+			return err
+		}
+	}
+	if c.Telegram.MaxWorkers < 0 {
+		return fmt.Errorf("telegram.max_workers must not be negative")
+	}
+	if c.Telegram.MaxResponseSize < 0 {
+		return fmt.Errorf("telegram.max_response_size must not be negative")
+	}
+	if c.Telegram.ChatCacheSize < 0 || c.Telegram.ChatCacheTTL < 0 {
+		return fmt.Errorf("telegram chat cache settings must not be negative")
+	}
+
+	if c.App.ShutdownTimeout <= 0 {
+		return fmt.Errorf("app.shutdown_timeout must be positive")
+	}
+	if c.Storage.MaxItems < 0 || c.Storage.MaxAge < 0 || c.Storage.CompactInterval < 0 {
+		return fmt.Errorf("storage retention settings must not be negative")
+	}
 
 	validEnvironments := map[string]bool{
 		"development": true,
@@ -137,6 +420,90 @@ func (c *Config) Validate() error {
 	return nil
 }
 
+// redactedValue заменяет секреты в Redacted
+const redactedValue = "***"
+
+// Redacted возвращает копию конфигурации со скрытыми секретами,
+// пригодную для вывода в логи и диагностические ответы
+func (c *Config) Redacted() *Config {
+	redacted := *c
+
+	redacted.Telegram.BotToken = redactSecret(c.Telegram.BotToken)
+
+	if c.Telegram.Profiles != nil {
+		redacted.Telegram.Profiles = make(map[string]TelegramProfile, len(c.Telegram.Profiles))
+		for name, profile := range c.Telegram.Profiles {
+			profile.BotToken = redactSecret(profile.BotToken)
+			redacted.Telegram.Profiles[name] = profile
+		}
+	}
+
+	return &redacted
+}
+
+// redactSecret скрывает непустой секрет
+func redactSecret(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return redactedValue
+}
+
+// reloadableFields поля (section.yaml_key), которые применяются без перезапуска.
+// Изменения всех остальных полей игнорируются и попадают в отчет MergeReloadable.
+var reloadableFields = map[string]bool{
+	"telegram.chat_id":            true,
+	"telegram.force_chat_id":      true,
+	"telegram.debug":              true,
+	"telegram.default_parse_mode": true,
+	"telegram.message_footer":     true,
+	"telegram.quiet_hours":        true,
+	"logging.level":               true,
+	"logging.format":              true,
+}
+
+// MergeReloadable возвращает копию текущей конфигурации, в которую перенесены
+// поля, допускающие изменение без перезапуска. Для остальных изменившихся полей
+// возвращается список имен, чтобы вызывающий мог предупредить о них.
+func MergeReloadable(current, next *Config) (*Config, []string) {
+	merged := *current
+
+	var ignored []string
+	mergedValue := reflect.ValueOf(&merged).Elem()
+	nextValue := reflect.ValueOf(next).Elem()
+	configType := mergedValue.Type()
+	for i := range configType.NumField() {
+		section := yamlName(configType.Field(i))
+		if section == "" || section == "-" {
+			continue
+		}
+
+		mergedSection := mergedValue.Field(i)
+		nextSection := nextValue.Field(i)
+		sectionType := mergedSection.Type()
+		for j := range sectionType.NumField() {
+			name := section + "." + yamlName(sectionType.Field(j))
+			mergedField, nextField := mergedSection.Field(j), nextSection.Field(j)
+			if reflect.DeepEqual(mergedField.Interface(), nextField.Interface()) {
+				continue
+			}
+			if reloadableFields[name] {
+				mergedField.Set(nextField)
+			} else {
+				ignored = append(ignored, name)
+			}
+		}
+	}
+
+	return &merged, ignored
+}
+
+// yamlName возвращает имя поля в YAML файле конфигурации
+func yamlName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+	return name
+}
+
 // IsProduction проверяет, production ли окружение
 func (c *Config) IsProduction() bool {
 	return c.App.Environment == "production"
@@ -148,39 +515,137 @@ func (c *Config) IsDevelopment() bool {
 }
 
 // overrideFromEnv переопределяет значения из environment variables
-func (c *Config) overrideFromEnv() {
-	if token := os.Getenv("TELEGRAM_BOT_TOKEN"); token != "" {
-		c.Telegram.BotToken = token
-	}
-	if chatID := os.Getenv("TELEGRAM_CHAT_ID"); chatID != "" {
-		c.Telegram.ChatID = chatID
+func (c *Config) overrideFromEnv() error {
+	overrideString(&c.Telegram.BotToken, "TELEGRAM_BOT_TOKEN")
+	overrideString(&c.Telegram.BotTokenFile, "TELEGRAM_BOT_TOKEN_FILE")
+	overrideString(&c.Telegram.ChatID, "TELEGRAM_CHAT_ID")
+	overrideString(&c.Telegram.ForceChatID, "TELEGRAM_FORCE_CHAT_ID")
+	overrideString(&c.Telegram.APIBaseURL, "TELEGRAM_API_BASE_URL")
+	overrideString(&c.Telegram.DefaultParseMode, "TELEGRAM_DEFAULT_PARSE_MODE")
+	overrideString(&c.Telegram.MessageFooter, "TELEGRAM_MESSAGE_FOOTER")
+	overrideString(&c.Telegram.TemplatesDir, "TELEGRAM_TEMPLATES_DIR")
+	overrideString(&c.Telegram.DeadLetterFile, "TELEGRAM_DEAD_LETTER_FILE")
+	if value := os.Getenv("TELEGRAM_TIMEOUT"); value != "" {
+		timeout, err := ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid TELEGRAM_TIMEOUT: %w", err)
+		}
+		c.Telegram.Timeout = timeout
 	}
 	if debug := os.Getenv("TELEGRAM_DEBUG"); debug != "" {
 		c.Telegram.Debug = debug == "true" || debug == "1"
 	}
+	if err := overrideBool(&c.App.WatchConfig, "APP_WATCH_CONFIG"); err != nil {
+		return err
+	}
+
+	intVars := []struct {
+		target *int
+		name   string
+	}{
+		{&c.Telegram.MaxIdleConns, "TELEGRAM_MAX_IDLE_CONNS"},
+		{&c.Telegram.MaxIdleConnsPerHost, "TELEGRAM_MAX_IDLE_CONNS_PER_HOST"},
+		{&c.Telegram.IdleConnTimeout, "TELEGRAM_IDLE_CONN_TIMEOUT"},
+		{&c.Telegram.BreakerThreshold, "TELEGRAM_BREAKER_THRESHOLD"},
+		{&c.Telegram.BreakerCooldown, "TELEGRAM_BREAKER_COOLDOWN"},
+		{&c.Telegram.BreakerMaxCooldown, "TELEGRAM_BREAKER_MAX_COOLDOWN"},
+		{&c.Telegram.DedupWindow, "TELEGRAM_DEDUP_WINDOW"},
+		{&c.Telegram.MaxWorkers, "TELEGRAM_MAX_WORKERS"},
+		{&c.Telegram.MaxResponseSize, "TELEGRAM_MAX_RESPONSE_SIZE"},
+		{&c.Telegram.ChatCacheSize, "TELEGRAM_CHAT_CACHE_SIZE"},
+		{&c.Telegram.ChatCacheTTL, "TELEGRAM_CHAT_CACHE_TTL"},
+		{&c.App.ShutdownTimeout, "APP_SHUTDOWN_TIMEOUT"},
+		{&c.Storage.MaxItems, "STORAGE_MAX_ITEMS"},
+	}
+	for _, v := range intVars {
+		if err := overrideInt(v.target, v.name); err != nil {
+			return err
+		}
+	}
+
+	overrideString(&c.App.Name, "APP_NAME")
+	overrideString(&c.App.Version, "APP_VERSION")
+	overrideString(&c.App.Environment, "APP_ENVIRONMENT")
+
+	overrideString(&c.Logging.Level, "LOG_LEVEL")
+	overrideString(&c.Logging.Format, "LOG_FORMAT")
+	overrideString(&c.Logging.StorageFile, "LOG_STORAGE_FILE")
+
+	if err := overrideConfigDuration(&c.Storage.MaxAge, "STORAGE_MAX_AGE"); err != nil {
+		return err
+	}
+	if err := overrideConfigDuration(&c.Storage.CompactInterval, "STORAGE_COMPACT_INTERVAL"); err != nil {
+		return err
+	}
+
+	return nil
 }
 
-// findConfigFile ищет конфигурационный файл в стандартных местах
-func findConfigFile() string {
-    // Можно добавить флаг командной строки
-    var configPath string
-    flag.StringVar(&configPath, "config", "", "path to config file")
-    flag.Parse()
-    
-    // Если путь указан через флаг, используем его
+// overrideString подставляет значение переменной окружения, если она задана
+func overrideString(target *string, name string) {
+	if value := os.Getenv(name); value != "" {
+		*target = value
+	}
+}
+
+// overrideInt подставляет целое значение переменной окружения, если она задана
+func overrideInt(target *int, name string) error {
+	value := os.Getenv(name)
+	if value == "" {
+		return nil
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", name, err)
+	}
+	*target = parsed
+
+	return nil
+}
+
+// overrideConfigDuration подставляет Duration ("10s" или число секунд) из переменной окружения
+func overrideConfigDuration(target *Duration, name string) error {
+	value := os.Getenv(name)
+	if value == "" {
+		return nil
+	}
+
+	parsed, err := ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", name, err)
+	}
+	*target = parsed
+
+	return nil
+}
+
+// configPathEnv переменная окружения с путем к файлу конфигурации
+const configPathEnv = "CONFIG_PATH"
+
+// findConfigFile ищет конфигурационный файл в стандартных местах;
+// configPath — значение флага -config (пусто — не задан). Путь, заданный
+// флагом или CONFIG_PATH, должен существовать: иначе возвращается ошибка,
+// а не конфигурация из другого источника.
+func findConfigFile(configPath string) (string, error) {
+    if configPath == "" {
+        configPath = os.Getenv(configPathEnv)
+    }
+
+    // Если путь указан явно, используем только его
     if configPath != "" {
-        if _, err := os.Stat(configPath); err == nil {
-            log.Printf("✓ Using config from flag: %s", configPath)
-            return configPath
+        if _, err := os.Stat(configPath); err != nil {
+            return "", fmt.Errorf("config file %s: %w", configPath, err)
         }
-        log.Printf("✗ Config file not found: %s", configPath)
+        log.Printf("✓ Using config: %s", configPath)
+        return configPath, nil
     }
     
     // Иначе ищем в рабочей директории
     wd, err := os.Getwd()
     if err != nil {
         log.Printf("Error getting working directory: %v", err)
-        return ""
+        return "", nil
     }
 
     possiblePaths := []string{
@@ -194,10 +659,10 @@ func findConfigFile() string {
     for _, path := range possiblePaths {
         if _, err := os.Stat(path); err == nil {
             log.Printf("✓ Found: %s", path)
-            return path
+            return path, nil
         }
         log.Printf("✗ Not found: %s", path)
     }
     
-    return ""
+    return "", nil
 }
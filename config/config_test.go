@@ -0,0 +1,422 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.yml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+const minimalConfig = `
+telegram:
+  bot_token: file-token
+  chat_id: "100"
+`
+
+func TestLoadConfigDefaults(t *testing.T) {
+	cfg, err := LoadConfig(writeConfig(t, minimalConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := DefaultConfig()
+	want.Telegram.BotToken = "file-token"
+	want.Telegram.ChatID = "100"
+	if cfg.App != want.App {
+		t.Errorf("App = %+v, want defaults %+v", cfg.App, want.App)
+	}
+	if cfg.Telegram.Timeout != want.Telegram.Timeout || cfg.Telegram.MaxWorkers != want.Telegram.MaxWorkers ||
+		cfg.Telegram.BreakerThreshold != want.Telegram.BreakerThreshold || cfg.Telegram.APIBaseURL != want.Telegram.APIBaseURL {
+		t.Errorf("Telegram defaults not applied: %+v", cfg.Telegram)
+	}
+}
+
+func TestLoadConfigPrecedence(t *testing.T) {
+	tests := []struct {
+		name  string
+		file  string
+		env   map[string]string
+		check func(t *testing.T, cfg *Config)
+	}{
+		{
+			name: "file overrides defaults",
+			file: minimalConfig + "  max_workers: 3\napp:\n  shutdown_timeout: 9\n",
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Telegram.MaxWorkers != 3 || cfg.App.ShutdownTimeout != 9 {
+					t.Errorf("max_workers=%d shutdown_timeout=%d, want 3 and 9", cfg.Telegram.MaxWorkers, cfg.App.ShutdownTimeout)
+				}
+			},
+		},
+		{
+			name: "environment overrides file",
+			file: minimalConfig + "  timeout: 3s\n",
+			env:  map[string]string{"TELEGRAM_CHAT_ID": "200", "TELEGRAM_TIMEOUT": "500ms", "STORAGE_MAX_ITEMS": "50"},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Telegram.ChatID != "200" || cfg.Telegram.Timeout.Duration() != 500*time.Millisecond || cfg.Storage.MaxItems != 50 {
+					t.Errorf("chat_id=%s timeout=%s max_items=%d", cfg.Telegram.ChatID, cfg.Telegram.Timeout, cfg.Storage.MaxItems)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+			cfg, err := LoadConfig(writeConfig(t, tt.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.check(t, cfg)
+		})
+	}
+}
+
+func TestLoadConfigValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		wantErr string
+	}{
+		{name: "zero shutdown timeout", file: minimalConfig + "app:\n  shutdown_timeout: 0\n", wantErr: "shutdown_timeout"},
+		{name: "negative storage retention", file: minimalConfig + "storage:\n  max_items: -1\n", wantErr: "storage"},
+		{name: "missing chat", file: "telegram:\n  bot_token: x\n", wantErr: "chat_id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadConfig(writeConfig(t, tt.file))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadConfig error = %v, want it to mention %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadConfigFromEnvironment(t *testing.T) {
+	// Пустой рабочий каталог: файла конфигурации нет ни в одном из стандартных мест
+	t.Chdir(t.TempDir())
+	t.Setenv(configPathEnv, "")
+
+	path, err := findConfigFile("")
+	if err != nil || path != "" {
+		t.Fatalf("findConfigFile = %q, %v; want no file", path, err)
+	}
+
+	t.Setenv("TELEGRAM_BOT_TOKEN", "env-token")
+	t.Setenv("TELEGRAM_CHAT_ID", "100")
+	t.Setenv("TELEGRAM_TIMEOUT", "15s")
+	t.Setenv("APP_ENVIRONMENT", "staging")
+	t.Setenv("LOG_LEVEL", "debug")
+	t.Setenv("STORAGE_MAX_ITEMS", "500")
+
+	cfg, err := loadConfigFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Telegram.BotToken != "env-token" || cfg.Telegram.ChatID != "100" ||
+		cfg.Telegram.Timeout.Duration() != 15*time.Second || cfg.App.Environment != "staging" ||
+		cfg.Logging.Level != "debug" || cfg.Storage.MaxItems != 500 {
+		t.Errorf("environment not applied: %+v", cfg)
+	}
+	if cfg.Path != "" {
+		t.Errorf("Path = %q, want empty for an environment-only config", cfg.Path)
+	}
+	// Остальные поля получают значения по умолчанию
+	if cfg.Telegram.MaxWorkers != DefaultConfig().Telegram.MaxWorkers {
+		t.Errorf("MaxWorkers = %d, want the default", cfg.Telegram.MaxWorkers)
+	}
+
+	// Конфигурация из окружения проходит ту же валидацию
+	t.Setenv("APP_ENVIRONMENT", "qa")
+	if _, err := loadConfigFromEnv(); err == nil {
+		t.Error("invalid environment accepted")
+	}
+}
+
+func TestFindConfigFileExplicitPath(t *testing.T) {
+	existing := writeConfig(t, minimalConfig)
+	missing := filepath.Join(t.TempDir(), "missing.yml")
+
+	tests := []struct {
+		name    string
+		flag    string
+		env     string
+		want    string
+		wantErr bool
+	}{
+		{name: "flag", flag: existing, want: existing},
+		{name: "environment", env: existing, want: existing},
+		{name: "flag wins over environment", flag: existing, env: missing, want: existing},
+		{name: "missing flag path", flag: missing, wantErr: true},
+		{name: "missing environment path", env: missing, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(configPathEnv, tt.env)
+
+			got, err := findConfigFile(tt.flag)
+			if tt.wantErr {
+				if !errors.Is(err, os.ErrNotExist) {
+					t.Errorf("error = %v, want not exist", err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("findConfigFile = %q, %v; want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestFileLoadConfigArgs(t *testing.T) {
+	file := writeConfig(t, "monitor:\n  input: file.json\n  keep: 2\n  output: file-out.json\n")
+
+	tests := []struct {
+		name    string
+		args    []string
+		env     map[string]string
+		want    func(cfg FileConfig) bool
+		wantErr bool
+	}{
+		{
+			name: "defaults",
+			want: func(cfg FileConfig) bool {
+				return cfg.InputFile == "services.json" && cfg.InputTimeout == 30*time.Second && cfg.OutputTimeout == 30*time.Second
+			},
+		},
+		{
+			name: "file",
+			args: []string{"-config", file},
+			want: func(cfg FileConfig) bool {
+				return cfg.InputFile == "file.json" && cfg.KeepFiles == 2 && cfg.OutputFile == "file-out.json"
+			},
+		},
+		{
+			name: "environment overrides file",
+			args: []string{"-config", file},
+			env:  map[string]string{"MONITOR_KEEP": "5"},
+			want: func(cfg FileConfig) bool { return cfg.KeepFiles == 5 && cfg.InputFile == "file.json" },
+		},
+		{
+			name: "flags override environment and file",
+			args: []string{"-config", file, "-keep", "7", "-input", "flag.json", "-output-url", "http://a", "-output-url", "http://b"},
+			env:  map[string]string{"MONITOR_KEEP": "5"},
+			want: func(cfg FileConfig) bool {
+				return cfg.KeepFiles == 7 && cfg.InputFile == "flag.json" && cfg.OutputFile == "file-out.json" &&
+					slices.Equal(cfg.OutputURLs, []string{"http://a", "http://b"})
+			},
+		},
+		{
+			name: "output timeout",
+			env:  map[string]string{"MONITOR_OUTPUT_TIMEOUT": "5s"},
+			want: func(cfg FileConfig) bool { return cfg.OutputTimeout == 5*time.Second },
+		},
+		{
+			name: "output timeout flag",
+			args: []string{"-output-timeout", "0"},
+			env:  map[string]string{"MONITOR_OUTPUT_TIMEOUT": "5s"},
+			want: func(cfg FileConfig) bool { return cfg.OutputTimeout == 0 },
+		},
+		{name: "unknown flag", args: []string{"-bogus"}, wantErr: true},
+		{name: "invalid field map", args: []string{"-field-map", "broken"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Файл конфигурации берется только из -config
+			t.Chdir(t.TempDir())
+			t.Setenv(configPathEnv, "")
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+
+			cfg, err := FileLoadConfigArgs(tt.args)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !tt.want(cfg) {
+				t.Errorf("unexpected config: %+v", cfg)
+			}
+		})
+	}
+}
+
+func TestLoadConfigArgs(t *testing.T) {
+	file := writeConfig(t, minimalConfig)
+
+	tests := []struct {
+		name     string
+		args     []string
+		env      map[string]string
+		wantChat string
+		wantPath string
+		wantErr  bool
+	}{
+		{name: "config flag", args: []string{"-config", file}, wantChat: "100", wantPath: file},
+		{
+			name:     "no flags",
+			env:      map[string]string{"TELEGRAM_BOT_TOKEN": "env-token", "TELEGRAM_CHAT_ID": "200"},
+			wantChat: "200",
+		},
+		{name: "missing config file", args: []string{"-config", filepath.Join(t.TempDir(), "missing.yml")}, wantErr: true},
+		// Флаги monitor не регистрируются в notifier
+		{name: "monitor flag", args: []string{"-config", file, "-input", "services.json"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Chdir(t.TempDir())
+			t.Setenv(configPathEnv, "")
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+
+			cfg, err := LoadConfigArgs(tt.args)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg.Telegram.ChatID != tt.wantChat || cfg.Path != tt.wantPath {
+				t.Errorf("chat_id=%q path=%q, want %q and %q", cfg.Telegram.ChatID, cfg.Path, tt.wantChat, tt.wantPath)
+			}
+		})
+	}
+}
+
+func TestConfigLoadersInOneProcess(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv(configPathEnv, "")
+	file := writeConfig(t, minimalConfig+"monitor:\n  input: file.json\n")
+
+	// Повторный разбор не паникует с "flag redefined"
+	for range 2 {
+		cfg, err := LoadConfigArgs([]string{"-config", file})
+		if err != nil || cfg.Telegram.ChatID != "100" {
+			t.Fatalf("LoadConfigArgs = %+v, %v", cfg, err)
+		}
+		fileCfg, err := FileLoadConfigArgs([]string{"-config", file, "-keep", "3"})
+		if err != nil || fileCfg.InputFile != "file.json" || fileCfg.KeepFiles != 3 {
+			t.Fatalf("FileLoadConfigArgs = %+v, %v", fileCfg, err)
+		}
+	}
+
+	// Аргументы monitor не подходят notifier
+	if _, err := LoadConfigArgs([]string{"-config", file, "-keep", "3"}); err == nil {
+		t.Error("notifier accepted a monitor flag")
+	}
+	// Загрузчики не трогают глобальный набор флагов
+	for _, name := range []string{"config", "input", "keep"} {
+		if flag.CommandLine.Lookup(name) != nil {
+			t.Errorf("flag -%s registered in flag.CommandLine", name)
+		}
+	}
+}
+
+func TestRedacted(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.App.Name = "notifier"
+	cfg.Telegram.BotToken = "123:main-secret"
+	cfg.Telegram.ChatID = "100"
+	cfg.Telegram.Profiles = map[string]TelegramProfile{
+		"ops":   {BotToken: "456:ops-secret", ChatID: "200"},
+		"empty": {ChatID: "300"},
+	}
+
+	redacted := cfg.Redacted()
+	if redacted.Telegram.BotToken != redactedValue || redacted.Telegram.Profiles["ops"].BotToken != redactedValue {
+		t.Errorf("tokens not masked: %+v", redacted.Telegram)
+	}
+	// Пустой секрет остается пустым, чтобы было видно, что он не задан
+	if redacted.Telegram.Profiles["empty"].BotToken != "" {
+		t.Errorf("empty token shown as %q", redacted.Telegram.Profiles["empty"].BotToken)
+	}
+	if redacted.App.Name != "notifier" || redacted.Telegram.ChatID != "100" || redacted.Telegram.Profiles["ops"].ChatID != "200" {
+		t.Errorf("non-secret fields changed: %+v", redacted)
+	}
+
+	data, err := json.Marshal(redacted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "secret") {
+		t.Errorf("redacted config leaks a secret: %s", data)
+	}
+
+	// Исходная конфигурация не меняется
+	if cfg.Telegram.BotToken != "123:main-secret" || cfg.Telegram.Profiles["ops"].BotToken != "456:ops-secret" {
+		t.Errorf("Redacted modified the original config: %+v", cfg.Telegram)
+	}
+}
+
+func TestMergeReloadable(t *testing.T) {
+	tests := []struct {
+		name        string
+		change      func(cfg *Config)
+		wantIgnored []string
+		applied     func(cfg *Config) bool
+	}{
+		{
+			name:    "reloadable fields are applied",
+			change:  func(cfg *Config) { cfg.Telegram.ChatID = "200"; cfg.Logging.Level = "debug" },
+			applied: func(cfg *Config) bool { return cfg.Telegram.ChatID == "200" && cfg.Logging.Level == "debug" },
+		},
+		{
+			name: "other fields are reported and kept",
+			change: func(cfg *Config) {
+				cfg.Telegram.MaxWorkers = 50
+				cfg.Telegram.Profiles = map[string]TelegramProfile{"ops": {BotToken: "x"}}
+				cfg.Logging.StorageFile = "events.jsonl"
+				cfg.App.ShutdownTimeout = 30
+			},
+			wantIgnored: []string{"telegram.profiles", "telegram.max_workers", "app.shutdown_timeout", "logging.storage_file"},
+			applied: func(cfg *Config) bool {
+				return cfg.Telegram.MaxWorkers == 10 && cfg.Telegram.Profiles == nil && cfg.Logging.StorageFile == "" && cfg.App.ShutdownTimeout == 5
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current := DefaultConfig()
+			next := DefaultConfig()
+			tt.change(next)
+
+			merged, ignored := MergeReloadable(current, next)
+			if !slices.Equal(ignored, tt.wantIgnored) {
+				t.Errorf("ignored = %v, want %v", ignored, tt.wantIgnored)
+			}
+			if !tt.applied(merged) {
+				t.Errorf("unexpected merged config: %+v", merged)
+			}
+		})
+	}
+}
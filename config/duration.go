@@ -0,0 +1,70 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Duration длительность в конфигурации. Записывается строкой в формате
+// time.ParseDuration ("10s", "500ms") или, для совместимости со старыми
+// файлами, целым числом секунд.
+type Duration time.Duration
+
+// ParseDuration разбирает длительность; целое число без единиц — секунды
+func ParseDuration(value string) (Duration, error) {
+	value = strings.TrimSpace(value)
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return Duration(time.Duration(seconds) * time.Second), nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q: expected e.g. \"10s\", \"500ms\" or seconds", value)
+	}
+	return Duration(d), nil
+}
+
+// Duration возвращает значение как time.Duration
+func (d Duration) Duration() time.Duration {
+	return time.Duration(d)
+}
+
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+func (d *Duration) UnmarshalYAML(node *yaml.Node) error {
+	parsed, err := ParseDuration(node.Value)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+func (d Duration) MarshalYAML() (any, error) {
+	return d.String(), nil
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	parsed, err := ParseDuration(fmt.Sprint(value))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
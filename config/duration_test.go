@@ -0,0 +1,101 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{value: "500ms", want: 500 * time.Millisecond},
+		{value: "30", want: 30 * time.Second},
+		{value: " 10s ", want: 10 * time.Second},
+		{value: "1m30s", want: 90 * time.Second},
+		{value: "0", want: 0},
+		{value: "ten", wantErr: true},
+		{value: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseDuration(tt.value)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseDuration(%q) = %v, want an error", tt.value, got)
+			}
+			continue
+		}
+		if err != nil || got.Duration() != tt.want {
+			t.Errorf("ParseDuration(%q) = %v, %v; want %v", tt.value, got, err, tt.want)
+		}
+	}
+}
+
+func TestDurationYAMLAndJSON(t *testing.T) {
+	var fromYAML struct {
+		Short Duration `yaml:"short"`
+		Long  Duration `yaml:"long"`
+	}
+	if err := yaml.Unmarshal([]byte("short: 500ms\nlong: 30\n"), &fromYAML); err != nil {
+		t.Fatal(err)
+	}
+	if fromYAML.Short.Duration() != 500*time.Millisecond || fromYAML.Long.Duration() != 30*time.Second {
+		t.Errorf("YAML: short=%v long=%v", fromYAML.Short, fromYAML.Long)
+	}
+
+	var fromJSON struct {
+		Short Duration `json:"short"`
+		Long  Duration `json:"long"`
+	}
+	if err := json.Unmarshal([]byte(`{"short": "500ms", "long": 30}`), &fromJSON); err != nil {
+		t.Fatal(err)
+	}
+	if fromJSON.Short.Duration() != 500*time.Millisecond || fromJSON.Long.Duration() != 30*time.Second {
+		t.Errorf("JSON: short=%v long=%v", fromJSON.Short, fromJSON.Long)
+	}
+
+	// Сериализация всегда в формате time.Duration
+	data, err := json.Marshal(fromJSON)
+	if err != nil || string(data) != `{"short":"500ms","long":"30s"}` {
+		t.Errorf("json.Marshal = %s, %v", data, err)
+	}
+}
+
+func TestTimeoutFromFileAndEnvironment(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		env  string
+		want time.Duration
+	}{
+		{name: "milliseconds in YAML", file: minimalConfig + "  timeout: 500ms\n", want: 500 * time.Millisecond},
+		{name: "seconds in YAML", file: minimalConfig + "  timeout: 30\n", want: 30 * time.Second},
+		{name: "milliseconds in env", file: minimalConfig, env: "500ms", want: 500 * time.Millisecond},
+		{name: "seconds in env", file: minimalConfig, env: "30", want: 30 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TELEGRAM_TIMEOUT", tt.env)
+
+			cfg, err := LoadConfig(writeConfig(t, tt.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg.Telegram.Timeout.Duration() != tt.want {
+				t.Errorf("timeout = %v, want %v", cfg.Telegram.Timeout, tt.want)
+			}
+		})
+	}
+
+	t.Setenv("TELEGRAM_TIMEOUT", "soon")
+	if _, err := LoadConfig(writeConfig(t, minimalConfig)); err == nil {
+		t.Error("invalid TELEGRAM_TIMEOUT accepted")
+	}
+}
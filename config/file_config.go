@@ -0,0 +1,122 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultFileConfig возвращает конфигурацию monitor по умолчанию
+func DefaultFileConfig() FileConfig {
+	return FileConfig{
+		InputFile:     "services.json",
+		OutputFile:    "filtered_services.json",
+		InputTimeout:  30 * time.Second,
+		OutputTimeout: 30 * time.Second,
+	}
+}
+
+// loadYAML читает секцию monitor из файла конфигурации. Остальные секции
+// файла (telegram, app, logging) относятся к notifier и игнорируются.
+func (c *FileConfig) loadYAML(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	file := struct {
+		Monitor *FileConfig `yaml:"monitor"`
+	}{Monitor: c}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("failed to parse YAML config: %w", err)
+	}
+
+	return nil
+}
+
+// overrideFromEnv переопределяет значения из переменных окружения MONITOR_*
+func (c *FileConfig) overrideFromEnv() error {
+	overrideString(&c.InputFile, "MONITOR_INPUT")
+	overrideString(&c.OutputFile, "MONITOR_OUTPUT")
+	overrideString(&c.Validation, "MONITOR_VALIDATE")
+	overrideString(&c.BusinessLine, "MONITOR_BUSINESS_LINE")
+	overrideString(&c.SummaryFile, "MONITOR_SUMMARY_FILE")
+
+	if err := overrideBool(&c.Lenient, "MONITOR_LENIENT"); err != nil {
+		return err
+	}
+	if err := overrideBool(&c.Rotate, "MONITOR_ROTATE"); err != nil {
+		return err
+	}
+	if err := overrideBool(&c.KeepOnEmpty, "MONITOR_KEEP_ON_EMPTY"); err != nil {
+		return err
+	}
+	if err := overrideInt(&c.KeepFiles, "MONITOR_KEEP"); err != nil {
+		return err
+	}
+	if err := overrideInt(&c.OutputBatchSize, "MONITOR_OUTPUT_BATCH_SIZE"); err != nil {
+		return err
+	}
+	if err := overrideDuration(&c.InputTimeout, "MONITOR_INPUT_TIMEOUT"); err != nil {
+		return err
+	}
+	if err := overrideDuration(&c.OutputTimeout, "MONITOR_OUTPUT_TIMEOUT"); err != nil {
+		return err
+	}
+	if err := overrideDuration(&c.LockWait, "MONITOR_LOCK_WAIT"); err != nil {
+		return err
+	}
+
+	if value := os.Getenv("MONITOR_FIELD_MAP"); value != "" {
+		mapping, err := parseFieldMapping(value)
+		if err != nil {
+			return fmt.Errorf("invalid MONITOR_FIELD_MAP: %w", err)
+		}
+		c.FieldMapping = mapping
+	}
+	if value := os.Getenv("MONITOR_OUTPUT_URLS"); value != "" {
+		c.OutputURLs = strings.Split(value, ",")
+	}
+	// Форматы дат могут содержать запятые, поэтому разделитель — ";"
+	if value := os.Getenv("MONITOR_DATE_LAYOUTS"); value != "" {
+		c.DateLayouts = strings.Split(value, ";")
+	}
+
+	return nil
+}
+
+// overrideBool подставляет логическое значение переменной окружения, если она задана
+func overrideBool(target *bool, name string) error {
+	value := os.Getenv(name)
+	if value == "" {
+		return nil
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", name, err)
+	}
+	*target = parsed
+
+	return nil
+}
+
+// overrideDuration подставляет длительность ("30s", "500ms") из переменной окружения
+func overrideDuration(target *time.Duration, name string) error {
+	value := os.Getenv(name)
+	if value == "" {
+		return nil
+	}
+
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", name, err)
+	}
+	*target = parsed
+
+	return nil
+}
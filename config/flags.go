@@ -0,0 +1,119 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"time"
+)
+
+// Flags флаги командной строки, общие для notifier и monitor. Флаги
+// регистрирует каждый бинарник в своем flag.FlagSet (RegisterFlags и, для
+// monitor, RegisterFileFlags), поэтому бинарник не принимает чужих флагов,
+// а аргументы можно разбирать повторно (например, в тестах).
+type Flags struct {
+	// Config путь к файлу конфигурации (пусто — CONFIG_PATH или поиск в рабочей директории)
+	Config string
+}
+
+// RegisterFlags регистрирует общие флаги в fs
+func RegisterFlags(fs *flag.FlagSet) *Flags {
+	f := &Flags{}
+	fs.StringVar(&f.Config, "config", "", "path to config file")
+	return f
+}
+
+// FileFlags флаги monitor. Значения переносятся в FileConfig, только если
+// флаг задан явно, чтобы значения по умолчанию не перекрывали файл и окружение.
+type FileFlags struct {
+	// set набор, в котором зарегистрированы флаги (для Visit в applyTo)
+	set *flag.FlagSet
+
+	input         string
+	output        string
+	businessLine  string
+	summaryFile   string
+	inputTimeout  time.Duration
+	outputTimeout time.Duration
+	lenient       bool
+	rotate        bool
+	keepOnEmpty   bool
+	keep          int
+	validation    string
+	fieldMap      string
+	lockWait      time.Duration
+	dateLayouts   []string
+	outputURLs    []string
+}
+
+// RegisterFileFlags регистрирует флаги monitor в fs
+func RegisterFileFlags(fs *flag.FlagSet) *FileFlags {
+	f := &FileFlags{set: fs}
+
+	fs.StringVar(&f.input, "input", "services.json", "services JSON file path or http(s) URL")
+	fs.StringVar(&f.output, "output", "filtered_services.json", "file to write the filtered services to")
+	fs.StringVar(&f.businessLine, "business-line", "", "business line of the services to select")
+	fs.StringVar(&f.summaryFile, "summary", "", "also write results grouped by tenant to this file")
+	fs.DurationVar(&f.inputTimeout, "input-timeout", 30*time.Second, "timeout for reading the input (0 disables it)")
+	fs.DurationVar(&f.outputTimeout, "output-timeout", 30*time.Second, "timeout for writing the results to the file and output URLs (0 disables it)")
+	fs.BoolVar(&f.lenient, "lenient", false, "skip malformed records in the input file")
+	fs.BoolVar(&f.rotate, "rotate", false, "write results to timestamped files instead of overwriting")
+	fs.BoolVar(&f.keepOnEmpty, "keep-on-empty", false, "leave the previous output file untouched when nothing matches")
+	fs.IntVar(&f.keep, "keep", 0, "number of rotated result files to keep (0 keeps all)")
+	fs.StringVar(&f.validation, "validate", "", "validate results before saving: drop or fail")
+	fs.StringVar(&f.fieldMap, "field-map", "", "input key mapping, e.g. service_name=name,line=businessLine")
+	fs.DurationVar(&f.lockWait, "lock-wait", 0, "how long to wait for another run to release the output file (0 fails immediately)")
+	fs.Func("output-url", "also POST the results to this http(s) URL (repeatable)", func(url string) error {
+		f.outputURLs = append(f.outputURLs, url)
+		return nil
+	})
+	fs.Func("date-layout", "Go time layout of deprecatedDate in the input file (repeatable)", func(layout string) error {
+		f.dateLayouts = append(f.dateLayouts, layout)
+		return nil
+	})
+
+	return f
+}
+
+// applyTo переносит в конфигурацию monitor только явно заданные флаги
+func (f *FileFlags) applyTo(cfg *FileConfig) error {
+	var err error
+	f.set.Visit(func(fl *flag.Flag) {
+		switch fl.Name {
+		case "input":
+			cfg.InputFile = f.input
+		case "output":
+			cfg.OutputFile = f.output
+		case "business-line":
+			cfg.BusinessLine = f.businessLine
+		case "summary":
+			cfg.SummaryFile = f.summaryFile
+		case "input-timeout":
+			cfg.InputTimeout = f.inputTimeout
+		case "output-timeout":
+			cfg.OutputTimeout = f.outputTimeout
+		case "lenient":
+			cfg.Lenient = f.lenient
+		case "rotate":
+			cfg.Rotate = f.rotate
+		case "keep-on-empty":
+			cfg.KeepOnEmpty = f.keepOnEmpty
+		case "keep":
+			cfg.KeepFiles = f.keep
+		case "validate":
+			cfg.Validation = f.validation
+		case "field-map":
+			cfg.FieldMapping, err = parseFieldMapping(f.fieldMap)
+			if err != nil {
+				err = fmt.Errorf("invalid -field-map: %w", err)
+			}
+		case "lock-wait":
+			cfg.LockWait = f.lockWait
+		case "date-layout":
+			cfg.DateLayouts = f.dateLayouts
+		case "output-url":
+			cfg.OutputURLs = f.outputURLs
+		}
+	})
+
+	return err
+}
@@ -0,0 +1,100 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultCriticalPriority приоритет, начиная с которого уведомления
+// отправляются в тихие часы, если critical_priority не задан
+const DefaultCriticalPriority = 10
+
+// QuietHoursConfig период тишины, в который некритичные уведомления
+// откладываются до его окончания. Период может переходить через полночь.
+type QuietHoursConfig struct {
+	// Start и End время суток "HH:MM"
+	Start string `yaml:"start" json:"start"`
+	End   string `yaml:"end" json:"end"`
+	// Timezone часовой пояс IANA (например, "Europe/Moscow"), по умолчанию локальный
+	Timezone string `yaml:"timezone" json:"timezone"`
+	// CriticalPriority уведомления с Priority не ниже этого значения
+	// отправляются и в период тишины (по умолчанию DefaultCriticalPriority).
+	// Должен быть больше нуля, иначе критичными оказались бы все уведомления
+	// с приоритетом по умолчанию.
+	CriticalPriority int `yaml:"critical_priority" json:"critical_priority"`
+}
+
+// UnmarshalYAML подставляет DefaultCriticalPriority, если critical_priority не задан
+func (q *QuietHoursConfig) UnmarshalYAML(node *yaml.Node) error {
+	type plain QuietHoursConfig
+	value := plain{CriticalPriority: DefaultCriticalPriority}
+	if err := node.Decode(&value); err != nil {
+		return err
+	}
+	*q = QuietHoursConfig(value)
+	return nil
+}
+
+// Validate проверяет формат времени, часовой пояс и порог критичности
+func (q QuietHoursConfig) Validate() error {
+	if q.CriticalPriority <= 0 {
+		return fmt.Errorf("telegram.quiet_hours.critical_priority must be positive, got %d", q.CriticalPriority)
+	}
+	_, err := q.Until(time.Now())
+	return err
+}
+
+// Until возвращает момент окончания периода тишины, если t в него попадает,
+// и нулевое время, если нет
+func (q QuietHoursConfig) Until(t time.Time) (time.Time, error) {
+	start, err := parseClock(q.Start)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid telegram.quiet_hours.start: %w", err)
+	}
+	end, err := parseClock(q.End)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid telegram.quiet_hours.end: %w", err)
+	}
+	if start == end {
+		return time.Time{}, fmt.Errorf("telegram.quiet_hours start and end must differ")
+	}
+
+	loc := time.Local
+	if q.Timezone != "" {
+		if loc, err = time.LoadLocation(q.Timezone); err != nil {
+			return time.Time{}, fmt.Errorf("invalid telegram.quiet_hours.timezone: %w", err)
+		}
+	}
+
+	local := t.In(loc)
+	at := func(days int, clock [2]int) time.Time {
+		return time.Date(local.Year(), local.Month(), local.Day()+days, clock[0], clock[1], 0, 0, loc)
+	}
+
+	switch {
+	case start[0]*60+start[1] < end[0]*60+end[1]:
+		// Период в пределах одних суток, например 13:00-14:00
+		if !local.Before(at(0, start)) && local.Before(at(0, end)) {
+			return at(0, end), nil
+		}
+	case !local.Before(at(0, start)):
+		// Период через полночь, t после начала: тишина до завтрашнего окончания
+		return at(1, end), nil
+	case local.Before(at(0, end)):
+		// Период через полночь, t до сегодняшнего окончания
+		return at(0, end), nil
+	}
+
+	return time.Time{}, nil
+}
+
+// parseClock разбирает время суток "HH:MM" в часы и минуты
+func parseClock(value string) ([2]int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return [2]int{}, fmt.Errorf("expected HH:MM, got %q", value)
+	}
+	return [2]int{t.Hour(), t.Minute()}, nil
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestQuietHoursUntil(t *testing.T) {
+	day := func(hour, minute int) time.Time { return time.Date(2026, 1, 1, hour, minute, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name  string
+		start string
+		end   string
+		now   time.Time
+		want  time.Time
+	}{
+		{name: "inside a same-day window", start: "13:00", end: "14:00", now: day(13, 30), want: day(14, 0)},
+		{name: "before a same-day window", start: "13:00", end: "14:00", now: day(12, 59)},
+		{name: "end is exclusive", start: "13:00", end: "14:00", now: day(14, 0)},
+		{name: "overnight window before midnight", start: "22:00", end: "07:00", now: day(23, 0), want: day(7, 0).AddDate(0, 0, 1)},
+		{name: "overnight window after midnight", start: "22:00", end: "07:00", now: day(3, 0), want: day(7, 0)},
+		{name: "outside an overnight window", start: "22:00", end: "07:00", now: day(12, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quiet := QuietHoursConfig{Start: tt.start, End: tt.end, Timezone: "UTC", CriticalPriority: DefaultCriticalPriority}
+			got, err := quiet.Until(tt.now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("Until(%v) = %v, want %v", tt.now, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuietHoursCriticalPriority(t *testing.T) {
+	const quiet = "  quiet_hours:\n    start: \"22:00\"\n    end: \"07:00\"\n"
+
+	cfg, err := LoadConfig(writeConfig(t, minimalConfig+quiet))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.Telegram.QuietHours.CriticalPriority; got != DefaultCriticalPriority {
+		t.Errorf("critical_priority = %d, want the default %d", got, DefaultCriticalPriority)
+	}
+
+	cfg, err = LoadConfig(writeConfig(t, minimalConfig+quiet+"    critical_priority: 3\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.Telegram.QuietHours.CriticalPriority; got != 3 {
+		t.Errorf("critical_priority = %d, want 3", got)
+	}
+
+	// С порогом 0 критичными были бы все уведомления
+	for _, value := range []string{"0", "-1"} {
+		_, err := LoadConfig(writeConfig(t, minimalConfig+quiet+"    critical_priority: "+value+"\n"))
+		if err == nil || !strings.Contains(err.Error(), "critical_priority") {
+			t.Errorf("critical_priority %s: error = %v", value, err)
+		}
+	}
+}
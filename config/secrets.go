@@ -0,0 +1,48 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// loadSecretFiles подставляет токены ботов из файлов bot_token_file
+// (например, секретов, смонтированных платформой развертывания)
+func (c *Config) loadSecretFiles() error {
+	if err := readSecretFile(&c.Telegram.BotToken, c.Telegram.BotTokenFile, "telegram.bot_token"); err != nil {
+		return err
+	}
+
+	for name, profile := range c.Telegram.Profiles {
+		if err := readSecretFile(&profile.BotToken, profile.BotTokenFile, "telegram.profiles."+name+".bot_token"); err != nil {
+			return err
+		}
+		c.Telegram.Profiles[name] = profile
+	}
+
+	return nil
+}
+
+// readSecretFile читает секрет из файла path в target, обрезая пробелы и
+// переводы строк. Секрет нельзя задать одновременно значением и файлом.
+func readSecretFile(target *string, path, name string) error {
+	if path == "" {
+		return nil
+	}
+	if *target != "" {
+		return fmt.Errorf("%s and %s_file are both set, use only one", name, name)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s_file: %w", name, err)
+	}
+
+	secret := strings.TrimSpace(string(data))
+	if secret == "" {
+		return fmt.Errorf("%s_file %s is empty", name, path)
+	}
+	*target = secret
+
+	return nil
+}
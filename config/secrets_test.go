@@ -0,0 +1,103 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadConfigSecretFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeSecret := func(name, content string) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	mainToken := writeSecret("main", "123:main-secret\n")
+	opsToken := writeSecret("ops", "  456:ops-secret  \n")
+	empty := writeSecret("empty", "\n")
+
+	tests := []struct {
+		name      string
+		file      string
+		env       map[string]string
+		wantToken string
+		wantOps   string
+		wantErr   string
+	}{
+		{
+			name:      "token from file",
+			file:      "telegram:\n  chat_id: \"100\"\n  bot_token_file: " + mainToken + "\n",
+			wantToken: "123:main-secret",
+		},
+		{
+			name:      "profile token from file",
+			file:      "telegram:\n  chat_id: \"100\"\n  bot_token: inline\n  profiles:\n    ops:\n      bot_token_file: " + opsToken + "\n",
+			wantToken: "inline",
+			wantOps:   "456:ops-secret",
+		},
+		{
+			name:      "file from environment",
+			file:      "telegram:\n  chat_id: \"100\"\n",
+			env:       map[string]string{"TELEGRAM_BOT_TOKEN_FILE": mainToken},
+			wantToken: "123:main-secret",
+		},
+		{
+			name:    "inline token and file",
+			file:    "telegram:\n  chat_id: \"100\"\n  bot_token: inline\n  bot_token_file: " + mainToken + "\n",
+			wantErr: "telegram.bot_token and telegram.bot_token_file are both set",
+		},
+		{
+			name:    "environment token and file",
+			file:    "telegram:\n  chat_id: \"100\"\n  bot_token_file: " + mainToken + "\n",
+			env:     map[string]string{"TELEGRAM_BOT_TOKEN": "env-token"},
+			wantErr: "are both set",
+		},
+		{
+			name:    "profile token and file",
+			file:    "telegram:\n  chat_id: \"100\"\n  bot_token: inline\n  profiles:\n    ops:\n      bot_token: x\n      bot_token_file: " + opsToken + "\n",
+			wantErr: "telegram.profiles.ops.bot_token and telegram.profiles.ops.bot_token_file",
+		},
+		{
+			name:    "empty file",
+			file:    "telegram:\n  chat_id: \"100\"\n  bot_token_file: " + empty + "\n",
+			wantErr: "is empty",
+		},
+		{
+			name:    "missing file",
+			file:    "telegram:\n  chat_id: \"100\"\n  bot_token_file: " + filepath.Join(dir, "missing") + "\n",
+			wantErr: "failed to read telegram.bot_token_file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+
+			cfg, err := LoadConfig(writeConfig(t, tt.file))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				// Ошибка не раскрывает содержимое секрета
+				if strings.Contains(err.Error(), "123:") || strings.Contains(err.Error(), "456:") {
+					t.Errorf("error leaks the secret: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg.Telegram.BotToken != tt.wantToken || cfg.Telegram.Profiles["ops"].BotToken != tt.wantOps {
+				t.Errorf("tokens = %q and %q, want %q and %q",
+					cfg.Telegram.BotToken, cfg.Telegram.Profiles["ops"].BotToken, tt.wantToken, tt.wantOps)
+			}
+		})
+	}
+}
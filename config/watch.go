@@ -0,0 +1,64 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// DefaultWatchDebounce пауза после последнего события файла перед перечитыванием.
+// Обновление ConfigMap в Kubernetes порождает несколько событий подряд.
+const DefaultWatchDebounce = time.Second
+
+// Watch следит за файлом конфигурации и вызывает onChange после его изменения,
+// объединяя события, пришедшие в пределах debounce. Следит за каталогом, а не
+// за самим файлом: ConfigMap обновляется заменой символической ссылки, и
+// наблюдение за исходным файлом при этом теряется. Блокируется до отмены ctx.
+func Watch(ctx context.Context, path string, debounce time.Duration, onChange func()) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create config watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	dir := filepath.Dir(path)
+	if err := watcher.Add(dir); err != nil {
+		return fmt.Errorf("failed to watch %s: %w", dir, err)
+	}
+
+	name := filepath.Base(path)
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			// "..data" — ссылка, которую Kubernetes переключает при обновлении ConfigMap
+			base := filepath.Base(event.Name)
+			if base != name && base != "..data" {
+				continue
+			}
+			if !event.Has(fsnotify.Write) && !event.Has(fsnotify.Create) && !event.Has(fsnotify.Rename) {
+				continue
+			}
+			timer.Reset(debounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			log.Printf("Config watcher error: %v", err)
+		case <-timer.C:
+			onChange()
+		}
+	}
+}
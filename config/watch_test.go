@@ -0,0 +1,61 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWatchDebounce(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yml")
+	if err := os.WriteFile(path, []byte("a: 1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var changes atomic.Int32
+	done := make(chan error, 1)
+	go func() {
+		done <- Watch(ctx, path, 50*time.Millisecond, func() { changes.Add(1) })
+	}()
+	// Даем наблюдателю подписаться на каталог
+	time.Sleep(50 * time.Millisecond)
+
+	// Другие файлы каталога не вызывают перечитывания
+	if err := os.WriteFile(filepath.Join(dir, "other.yml"), []byte("b: 1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// Несколько записей подряд объединяются в одно перечитывание
+	for i := range 5 {
+		if err := os.WriteFile(path, []byte("a: "+strconv.Itoa(i+2)+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for changes.Load() == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	time.Sleep(150 * time.Millisecond)
+	if got := changes.Load(); got != 1 {
+		t.Errorf("onChange called %d times, want 1", got)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Watch returned %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Error("Watch did not stop after cancel")
+	}
+}
@@ -2,4 +2,10 @@ module github.com/mdemidenko/monitoring-platform
 
 go 1.25.3
 
-require gopkg.in/yaml.v3 v3.0.1
+require (
+	github.com/fsnotify/fsnotify v1.10.1
+	github.com/google/uuid v1.6.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require golang.org/x/sys v0.13.0 // indirect
@@ -0,0 +1,116 @@
+package health
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// Статусы подсистем
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+// Checker проверка здоровья одной подсистемы
+type Checker interface {
+	Name() string
+	Check(ctx context.Context) error
+}
+
+// checkerFunc адаптер функции проверки к интерфейсу Checker
+type checkerFunc struct {
+	name  string
+	check func(ctx context.Context) error
+}
+
+func (c checkerFunc) Name() string                    { return c.name }
+func (c checkerFunc) Check(ctx context.Context) error { return c.check(ctx) }
+
+// NewChecker создает Checker из имени и функции проверки
+func NewChecker(name string, check func(ctx context.Context) error) Checker {
+	return checkerFunc{name: name, check: check}
+}
+
+// ComponentStatus результат проверки подсистемы
+type ComponentStatus struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Report сводный результат проверки всех подсистем
+type Report struct {
+	Healthy    bool                       `json:"healthy"`
+	Components map[string]ComponentStatus `json:"components"`
+}
+
+// Registry хранит зарегистрированные проверки подсистем
+type Registry struct {
+	mu       sync.RWMutex
+	checkers []Checker
+}
+
+// NewRegistry создает пустой реестр проверок
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register добавляет проверку подсистемы
+func (r *Registry) Register(checker Checker) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.checkers = append(r.checkers, checker)
+}
+
+// Names возвращает имена зарегистрированных подсистем
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.checkers))
+	for _, checker := range r.checkers {
+		names = append(names, checker.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CheckAll параллельно выполняет все проверки и собирает отчет.
+// Отчет здоров, только если здоровы все подсистемы.
+func (r *Registry) CheckAll(ctx context.Context) Report {
+	r.mu.RLock()
+	checkers := append([]Checker(nil), r.checkers...)
+	r.mu.RUnlock()
+
+	report := Report{
+		Healthy:    true,
+		Components: make(map[string]ComponentStatus, len(checkers)),
+	}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, checker := range checkers {
+		wg.Add(1)
+		go func(checker Checker) {
+			defer wg.Done()
+
+			status := ComponentStatus{Status: StatusOK}
+			if err := checker.Check(ctx); err != nil {
+				status = ComponentStatus{Status: StatusFail, Error: err.Error()}
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Components[checker.Name()] = status
+			if status.Status != StatusOK {
+				report.Healthy = false
+			}
+		}(checker)
+	}
+	wg.Wait()
+
+	return report
+}
@@ -0,0 +1,120 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestCheckAll(t *testing.T) {
+	ok := func(context.Context) error { return nil }
+
+	tests := []struct {
+		name        string
+		checkers    []Checker
+		wantHealthy bool
+		want        map[string]ComponentStatus
+	}{
+		{name: "no checkers", wantHealthy: true, want: map[string]ComponentStatus{}},
+		{
+			name:        "all healthy",
+			checkers:    []Checker{NewChecker("telegram", ok), NewChecker("storage", ok)},
+			wantHealthy: true,
+			want: map[string]ComponentStatus{
+				"telegram": {Status: StatusOK},
+				"storage":  {Status: StatusOK},
+			},
+		},
+		{
+			name: "one failing checker",
+			checkers: []Checker{
+				NewChecker("telegram", func(context.Context) error { return errors.New("getMe failed") }),
+				NewChecker("storage", ok),
+				NewChecker("storage_logger", ok),
+			},
+			want: map[string]ComponentStatus{
+				"telegram":       {Status: StatusFail, Error: "getMe failed"},
+				"storage":        {Status: StatusOK},
+				"storage_logger": {Status: StatusOK},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := NewRegistry()
+			for _, checker := range tt.checkers {
+				registry.Register(checker)
+			}
+
+			report := registry.CheckAll(context.Background())
+			if report.Healthy != tt.wantHealthy {
+				t.Errorf("Healthy = %v, want %v", report.Healthy, tt.wantHealthy)
+			}
+			if len(report.Components) != len(tt.want) {
+				t.Errorf("components = %v, want %v", report.Components, tt.want)
+			}
+			for name, want := range tt.want {
+				if got := report.Components[name]; got != want {
+					t.Errorf("%s = %+v, want %+v", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckAllRunsInParallel(t *testing.T) {
+	const delay = 100 * time.Millisecond
+	slow := func(ctx context.Context) error {
+		select {
+		case <-time.After(delay):
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	registry := NewRegistry()
+	for _, name := range []string{"a", "b", "c", "d"} {
+		registry.Register(NewChecker(name, slow))
+	}
+
+	start := time.Now()
+	report := registry.CheckAll(context.Background())
+	// Время проверки определяется самой медленной подсистемой, а не их суммой
+	if elapsed := time.Since(start); elapsed >= 3*delay {
+		t.Errorf("CheckAll took %v for four %v checks", elapsed, delay)
+	}
+	if !report.Healthy {
+		t.Errorf("report = %+v, want healthy", report)
+	}
+}
+
+func TestCheckAllContextDeadline(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(NewChecker("hanging", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	report := registry.CheckAll(ctx)
+	if report.Healthy || report.Components["hanging"].Status != StatusFail {
+		t.Errorf("report = %+v, want the hanging check to fail", report)
+	}
+}
+
+func TestNames(t *testing.T) {
+	registry := NewRegistry()
+	for _, name := range []string{"telegram", "storage", "storage_logger"} {
+		registry.Register(NewChecker(name, func(context.Context) error { return nil }))
+	}
+
+	if names := registry.Names(); !slices.Equal(names, []string{"storage", "storage_logger", "telegram"}) {
+		t.Errorf("Names() = %v", names)
+	}
+}
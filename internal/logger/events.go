@@ -0,0 +1,61 @@
+package logger
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"log"
+	"time"
+
+	"github.com/mdemidenko/monitoring-platform/internal/models"
+)
+
+// eventFlushInterval период сброса буфера событий в файл
+const eventFlushInterval = time.Second
+
+// Option настраивает StorageLogger
+type Option func(*StorageLogger)
+
+// WithEventWriter дописывает каждое обнаруженное изменение хранилища в w
+// строкой JSON. Запись буферизуется и сбрасывается раз в eventFlushInterval
+// и при остановке логгера; закрывать w должен вызывающий код после Stop.
+func WithEventWriter(w io.Writer) Option {
+	return func(sl *StorageLogger) {
+		sl.events = bufio.NewWriter(w)
+	}
+}
+
+// storageEvent строка журнала изменений хранилища
+type storageEvent struct {
+	Type             string                   `json:"type"`
+	DetectedAt       time.Time                `json:"detected_at"`
+	Notification     *models.Notification     `json:"notification,omitempty"`
+	SentNotification *models.SentNotification `json:"sent_notification,omitempty"`
+}
+
+// writeEvent добавляет событие в буфер; вызывается только из горутины monitor
+func (sl *StorageLogger) writeEvent(event storageEvent) {
+	if sl.events == nil {
+		return
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("⚠️  Не удалось сериализовать событие хранилища: %v", err)
+		return
+	}
+	data = append(data, '\n')
+	if _, err := sl.events.Write(data); err != nil {
+		log.Printf("⚠️  Не удалось записать событие хранилища: %v", err)
+	}
+}
+
+// flushEvents сбрасывает буфер событий в файл
+func (sl *StorageLogger) flushEvents() {
+	if sl.events == nil {
+		return
+	}
+	if err := sl.events.Flush(); err != nil {
+		log.Printf("⚠️  Не удалось сбросить журнал событий хранилища: %v", err)
+	}
+}
@@ -1,8 +1,12 @@
 package logger
 
 import (
+	"bufio"
 	"context"
+	"errors"
+	"fmt"
 	"log"
+	"sync/atomic"
 	"time"
 
 	"github.com/mdemidenko/monitoring-platform/internal/models"
@@ -13,96 +17,129 @@ import (
 type StorageLogger struct {
 	storage    *repository.MemoryStorage
 	interval   time.Duration
+	running    atomic.Bool
+	cancel     context.CancelFunc
+	done       chan struct{}
+
+	// events буфер журнала изменений (WithEventWriter), nil — журнал выключен
+	events *bufio.Writer
 }
 
 // NewStorageLogger создает новый логгер хранилища
-func NewStorageLogger(storage *repository.MemoryStorage, interval time.Duration) *StorageLogger {
-	return &StorageLogger{
+func NewStorageLogger(storage *repository.MemoryStorage, interval time.Duration, opts ...Option) *StorageLogger {
+	sl := &StorageLogger{
 		storage:   storage,
 		interval:  interval,
 	}
+	for _, opt := range opts {
+		opt(sl)
+	}
+	return sl
 }
 
 // Start запускает логгер в отдельной горутине с поддержкой контекста
 func (sl *StorageLogger) Start(ctx context.Context) {
 	log.Printf("📊 Логгер хранилища запущен (интервал проверки: %v)", sl.interval)
 
+	ctx, sl.cancel = context.WithCancel(ctx)
+	sl.done = make(chan struct{})
+
+	sl.running.Store(true)
 	go sl.monitor(ctx)
 }
 
-// Stop останавливает логгер (оставляем для обратной совместимости)
-func (sl *StorageLogger) Stop() {
-	log.Printf("📊 Логгер хранилища остановлен")
+// Stop останавливает логгер и ждет, пока он залогирует последние изменения.
+// Ожидание ограничено контекстом.
+func (sl *StorageLogger) Stop(ctx context.Context) error {
+	if sl.done == nil {
+		return nil
+	}
+	sl.cancel()
+
+	select {
+	case <-sl.done:
+		log.Printf("📊 Логгер хранилища остановлен")
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("storage logger stop: %w", ctx.Err())
+	}
+}
+
+// Check сообщает, работает ли логгер (для проверки здоровья)
+func (sl *StorageLogger) Check(ctx context.Context) error {
+	if !sl.running.Load() {
+		return errors.New("storage logger is not running")
+	}
+	return nil
 }
 
 // monitor осуществляет мониторинг изменений в хранилище
 func (sl *StorageLogger) monitor(ctx context.Context) {
-	// Состояние для отслеживания изменений
-	lastNotifications := make([]*models.Notification, 0)
-	lastSentNotifications := make([]*models.SentNotification, 0)
-	lastNotificationCount := 0
-	lastSentNotificationCount := 0
+	defer close(sl.done)
+	defer sl.running.Store(false)
+
+	// Порядковые номера, с которых начинаются еще не залогированные сущности
+	var notificationOffset, sentNotificationOffset int
 
 	ticker := time.NewTicker(sl.interval)
 	defer ticker.Stop()
 
+	flushTicker := time.NewTicker(eventFlushInterval)
+	defer flushTicker.Stop()
+
 	log.Printf("📊 Мониторинг хранилища начат")
 
 	for {
 		select {
 		case <-ctx.Done():
-			// Контекст отменен - завершаем работу
+			// Контекст отменен - логируем последние изменения и завершаем работу
+			sl.checkForChanges(&notificationOffset, &sentNotificationOffset)
+			sl.flushEvents()
 			log.Printf("📊 Логгер хранилища завершает работу")
 			return
 		case <-ticker.C:
-			sl.checkForChanges(&lastNotifications, &lastSentNotifications, 
-				&lastNotificationCount, &lastSentNotificationCount)
+			sl.checkForChanges(&notificationOffset, &sentNotificationOffset)
+		case <-flushTicker.C:
+			sl.flushEvents()
 		}
 	}
 }
 
-// checkForChanges проверяет изменения в хранилище и логирует новые структуры
-func (sl *StorageLogger) checkForChanges(
-	lastNotifications *[]*models.Notification,
-	lastSentNotifications *[]*models.SentNotification,
-	lastNotificationCount *int,
-	lastSentNotificationCount *int,
-) {
-	// Получаем текущее состояние хранилища
-	currentNotifications := sl.storage.GetNotifications()
-	currentSentNotifications := sl.storage.GetSentNotifications()
-	currentNotificationCount := len(currentNotifications)
-	currentSentNotificationCount := len(currentSentNotifications)
+// checkForChanges проверяет изменения в хранилище и логирует новые структуры.
+// Смещения — порядковые номера сохранения, они не сбиваются при сжатии хранилища.
+func (sl *StorageLogger) checkForChanges(notificationOffset, sentNotificationOffset *int) {
+	newNotifications, nextNotificationOffset := repository.EntitiesSince[*models.Notification](sl.storage, *notificationOffset)
+	newSentNotifications, nextSentNotificationOffset := repository.EntitiesSince[*models.SentNotification](sl.storage, *sentNotificationOffset)
 
 	hasChanges := false
 
 	// Проверяем изменения в Notification
-	if currentNotificationCount > *lastNotificationCount {
-		newNotifications := currentNotifications[*lastNotificationCount:]
+	if nextNotificationOffset > *notificationOffset {
 		for _, notification := range newNotifications {
-			log.Printf("📝 НОВЫЙ Notification: ChatID=%s, Text='%s'", 
-				notification.ChatID, notification.Text)
+			log.Printf("📝 НОВЫЙ Notification: ID=%s, ChatID=%s, Text='%s', CreatedAt=%s",
+				notification.ID, notification.ChatID, notification.Text,
+				notification.CreatedAt.Format(time.RFC3339))
+			sl.writeEvent(storageEvent{Type: "notification", DetectedAt: time.Now(), Notification: notification})
 		}
-		*lastNotifications = currentNotifications
-		*lastNotificationCount = currentNotificationCount
+		*notificationOffset = nextNotificationOffset
 		hasChanges = true
 	}
 
 	// Проверяем изменения в SentNotification
-	if currentSentNotificationCount > *lastSentNotificationCount {
-		newSentNotifications := currentSentNotifications[*lastSentNotificationCount:]
+	if nextSentNotificationOffset > *sentNotificationOffset {
 		for _, sentNotification := range newSentNotifications {
-			log.Printf("📝 НОВЫЙ SentNotification: MessageID=%d, ChatID=%d", 
-				sentNotification.MessageID, sentNotification.ChatID)
+			log.Printf("📝 НОВЫЙ SentNotification: MessageID=%d, ChatID=%d, SentAt=%s",
+				sentNotification.MessageID, sentNotification.ChatID,
+				sentNotification.SentAt.Format(time.RFC3339))
+			sl.writeEvent(storageEvent{Type: "sent_notification", DetectedAt: time.Now(), SentNotification: sentNotification})
 		}
-		*lastSentNotifications = currentSentNotifications
-		*lastSentNotificationCount = currentSentNotificationCount
+		*sentNotificationOffset = nextSentNotificationOffset
 		hasChanges = true
 	}
 
 	// Логируем общую статистику при изменениях
 	if hasChanges {
-		log.Printf("📊 Статистика: Notifications=%d, SentNotifications=%d", 
-			currentNotificationCount, currentSentNotificationCount)
+		log.Printf("📊 Статистика: Notifications=%d, SentNotifications=%d",
+			len(sl.storage.GetNotifications()), len(sl.storage.GetSentNotifications()))
 	}
 }
\ No newline at end of file
@@ -0,0 +1,188 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mdemidenko/monitoring-platform/internal/models"
+	"github.com/mdemidenko/monitoring-platform/internal/repository"
+)
+
+// readEvents читает журнал изменений, проверяя, что каждая строка — JSON
+func readEvents(t *testing.T, path string) []storageEvent {
+	t.Helper()
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	var events []storageEvent
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var event storageEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("line %q is not valid JSON: %v", scanner.Text(), err)
+		}
+		events = append(events, event)
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return events
+}
+
+func TestStorageLoggerEventFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	storage := repository.NewMemoryStorage()
+	sl := NewStorageLogger(storage, time.Millisecond, WithEventWriter(file))
+	sl.Start(context.Background())
+
+	notification := models.NewNotification("100", "disk full")
+	if err := storage.Store(notification); err != nil {
+		t.Fatal(err)
+	}
+
+	// Буфер сбрасывается периодически, не дожидаясь остановки логгера
+	deadline := time.Now().Add(3 * eventFlushInterval)
+	for len(readEvents(t, path)) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("events were not flushed while the logger was running")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	sent := &models.SentNotification{MessageID: 42, ChatID: 100, NotificationID: notification.ID, SentAt: time.Now()}
+	if err := storage.Store(sent); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := sl.Stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	events := readEvents(t, path)
+	if len(events) != 2 {
+		t.Fatalf("events = %+v, want 2", events)
+	}
+	if events[0].Type != "notification" || events[0].Notification == nil || events[0].Notification.Text != "disk full" {
+		t.Errorf("first event = %+v, want the notification", events[0])
+	}
+	if events[1].Type != "sent_notification" || events[1].SentNotification == nil || events[1].SentNotification.MessageID != 42 {
+		t.Errorf("second event = %+v, want the sent notification", events[1])
+	}
+	for _, event := range events {
+		if event.DetectedAt.IsZero() {
+			t.Errorf("event %q has no detected_at", event.Type)
+		}
+	}
+}
+
+// TestStorageLoggerConcurrentWrites запускается с -race: логгер читает
+// хранилище, пока в него пишут по одной, пакетами и пока оно сжимается
+func TestStorageLoggerConcurrentWrites(t *testing.T) {
+	tests := []struct {
+		name      string
+		retention repository.Retention
+	}{
+		{name: "without compaction"},
+		{name: "with compaction", retention: repository.Retention{MaxItems: 5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := repository.NewMemoryStorage()
+			var events bytes.Buffer
+			sl := NewStorageLogger(storage, time.Millisecond, WithEventWriter(&events))
+			sl.Start(context.Background())
+
+			const writers, perWriter = 4, 50
+			var wg sync.WaitGroup
+			for w := range writers {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for i := range perWriter {
+						notification := models.NewNotification("100", "alert "+strconv.Itoa(w*perWriter+i))
+						if err := storage.Store(notification); err != nil {
+							t.Error(err)
+							return
+						}
+						sent := &models.SentNotification{MessageID: int64(i), NotificationID: notification.ID, SentAt: time.Now()}
+						if err := storage.StoreBatch([]any{sent}); err != nil {
+							t.Error(err)
+							return
+						}
+					}
+				}()
+			}
+
+			stop := make(chan struct{})
+			compacted := make(chan struct{})
+			go func() {
+				defer close(compacted)
+				for {
+					select {
+					case <-stop:
+						return
+					default:
+						if tt.retention.Enabled() {
+							storage.Compact(tt.retention)
+						}
+						time.Sleep(100 * time.Microsecond)
+					}
+				}
+			}()
+
+			wg.Wait()
+			close(stop)
+			<-compacted
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := sl.Stop(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			counts := map[string]int{}
+			decoder := json.NewDecoder(&events)
+			for decoder.More() {
+				var event storageEvent
+				if err := decoder.Decode(&event); err != nil {
+					t.Fatal(err)
+				}
+				counts[event.Type]++
+			}
+
+			// Сжатие между проверками логгера может удалить еще не
+			// залогированные записи, но ни одна не логируется дважды
+			total := writers * perWriter
+			if tt.retention.Enabled() {
+				if counts["notification"] > total || counts["sent_notification"] > total {
+					t.Errorf("events = %v, want at most %d of each", counts, total)
+				}
+				return
+			}
+			if counts["notification"] != total || counts["sent_notification"] != total {
+				t.Errorf("events = %v, want %d of each", counts, total)
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package models
+
+// Chat метаданные чата Telegram, возвращаемые методом getChat
+type Chat struct {
+	ID        int64  `json:"id"`
+	Type      string `json:"type"`
+	Title     string `json:"title,omitempty"`
+	Username  string `json:"username,omitempty"`
+	FirstName string `json:"first_name,omitempty"`
+	LastName  string `json:"last_name,omitempty"`
+}
+
+// DisplayName возвращает имя чата для отображения
+func (c *Chat) DisplayName() string {
+	switch {
+	case c.Title != "":
+		return c.Title
+	case c.FirstName != "" && c.LastName != "":
+		return c.FirstName + " " + c.LastName
+	case c.FirstName != "":
+		return c.FirstName
+	default:
+		return c.Username
+	}
+}
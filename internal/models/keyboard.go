@@ -0,0 +1,67 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+// MaxCallbackDataLength максимальная длина callback_data кнопки в байтах
+const MaxCallbackDataLength = 64
+
+// InlineKeyboardMarkup встроенная клавиатура под сообщением
+type InlineKeyboardMarkup struct {
+	InlineKeyboard [][]InlineKeyboardButton `json:"inline_keyboard"`
+}
+
+// InlineKeyboardButton кнопка встроенной клавиатуры: ссылка или callback
+type InlineKeyboardButton struct {
+	Text         string `json:"text"`
+	URL          string `json:"url,omitempty"`
+	CallbackData string `json:"callback_data,omitempty"`
+}
+
+// Validate проверяет клавиатуру на соответствие ограничениям Telegram
+func (m *InlineKeyboardMarkup) Validate() error {
+	if len(m.InlineKeyboard) == 0 {
+		return errors.New("inline keyboard has no rows")
+	}
+
+	for i, row := range m.InlineKeyboard {
+		if len(row) == 0 {
+			return fmt.Errorf("inline keyboard row %d is empty", i)
+		}
+		for j, button := range row {
+			if err := button.Validate(); err != nil {
+				return fmt.Errorf("inline keyboard button [%d][%d]: %w", i, j, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Validate проверяет кнопку: текст обязателен, ровно одно действие
+func (b InlineKeyboardButton) Validate() error {
+	if b.Text == "" {
+		return errors.New("text is required")
+	}
+
+	switch {
+	case b.URL != "" && b.CallbackData != "":
+		return errors.New("only one of url and callback_data may be set")
+	case b.URL != "":
+		u, err := url.Parse(b.URL)
+		if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "tg") {
+			return fmt.Errorf("invalid url: %q", b.URL)
+		}
+	case b.CallbackData != "":
+		if len(b.CallbackData) > MaxCallbackDataLength {
+			return fmt.Errorf("callback_data exceeds %d bytes", MaxCallbackDataLength)
+		}
+	default:
+		return errors.New("one of url and callback_data is required")
+	}
+
+	return nil
+}
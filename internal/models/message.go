@@ -1,21 +1,58 @@
 package models
 
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
 // Notification модель для отправки уведомления
 type Notification struct {
-	ChatID string `json:"chat_id"`
-	Text   string `json:"text"`
+	ID        string    `json:"id"`
+	ChatID    string    `json:"chat_id"`
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// ParseMode переопределяет parse_mode по умолчанию из конфигурации
+	ParseMode string `json:"parse_mode,omitempty"`
+	// SkipFooter отключает добавление подписи из конфигурации
+	SkipFooter bool `json:"skip_footer,omitempty"`
+	// Profile имя профиля Telegram (бот и чат), по умолчанию основной бот
+	Profile string `json:"profile,omitempty"`
+	// Priority приоритет отправки в пакете: уведомления с большим значением
+	// отправляются раньше, при равном приоритете сохраняется порядок
+	Priority int `json:"priority,omitempty"`
+	// ThreadID тема (topic) супергруппы, в которую отправляется сообщение
+	ThreadID int `json:"thread_id,omitempty"`
+	// ReplyToMessageID сообщение, ответом на которое отправляется уведомление
+	ReplyToMessageID int64 `json:"reply_to_message_id,omitempty"`
+	// DisableWebPagePreview отключает предпросмотр ссылок в сообщении
+	DisableWebPagePreview bool `json:"disable_web_page_preview,omitempty"`
+	// SplitLong разбивать текст длиннее лимита Telegram на несколько
+	// сообщений (по умолчанию включено)
+	SplitLong *bool `json:"split_long,omitempty"`
+	// ReplyMarkup встроенная клавиатура с кнопками под сообщением
+	ReplyMarkup *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	// Template имя шаблона, по которому формируется Text из Data
+	Template string         `json:"template,omitempty"`
+	Data     map[string]any `json:"data,omitempty"`
 }
 
 // SentNotification модель отправленного уведомления
 type SentNotification struct {
-	MessageID int64 `json:"message_id"`
-	ChatID    int64 `json:"chat_id"`
+	MessageID int64     `json:"message_id"`
+	ChatID    int64     `json:"chat_id"`
+	SentAt    time.Time `json:"sent_at"`
+	// NotificationID идентификатор исходного Notification
+	NotificationID string `json:"notification_id,omitempty"`
 }
 
 // NewNotification создает новое уведомление
 func NewNotification(chatID, text string) *Notification {
 	return &Notification{
-		ChatID: chatID,
-		Text:   text,
+		ID:        uuid.NewString(),
+		ChatID:    chatID,
+		Text:      text,
+		CreatedAt: time.Now(),
 	}
-}
\ No newline at end of file
+}
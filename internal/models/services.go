@@ -14,4 +14,15 @@ type Result struct {
 	ID     int    `json:"id"`
 	Name   string `json:"name"`
 	Tenant string `json:"tenant"`
-}
\ No newline at end of file
+}
+// Сводка результатов фильтрации по тенантам
+type ResultSummary struct {
+	Total   int                      `json:"total"`
+	Tenants map[string]TenantResults `json:"tenants"`
+}
+
+// Результаты одного тенанта
+type TenantResults struct {
+	Count   int      `json:"count"`
+	Results []Result `json:"results"`
+}
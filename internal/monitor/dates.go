@@ -0,0 +1,42 @@
+package monitor
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultDateLayouts форматы DeprecatedDate, которые разбираются по умолчанию
+var DefaultDateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// parseDeprecatedDate разбирает дату по первому подходящему формату.
+// Пустая строка означает, что дата не задана.
+func parseDeprecatedDate(value string, layouts []string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("неизвестный формат даты: %q", value)
+}
+
+// isZeroDate проверяет, что дата равна 0001-01-01 00:00:00 в своем
+// часовом поясе, то есть сервис не выведен из эксплуатации
+func isZeroDate(t time.Time) bool {
+	year, month, day := t.Date()
+	hour, minute, second := t.Clock()
+
+	return year == 1 && month == time.January && day == 1 &&
+		hour == 0 && minute == 0 && second == 0 && t.Nanosecond() == 0
+}
@@ -2,6 +2,8 @@ package monitor
 
 
 import (
+	"log"
+
 	"github.com/mdemidenko/monitoring-platform/internal/models"
 	"github.com/mdemidenko/monitoring-platform/internal/repository"
 )
@@ -12,16 +14,47 @@ type Service interface {
 }
 
 const (
+	// TargetDeprecatedDate каноническое значение "не выведен из эксплуатации";
+	// сравнение выполняется по разобранной дате, см. DefaultDateLayouts
 	TargetDeprecatedDate = "0001-01-01T00:00:00Z"
 	TargetBusinessLine   = "Управление разработки решений для бизнеса и Центр оптимизации процессов поставки"
 )
 
 type service struct {
-	repo repository.Repository
+	repo         repository.Repository
+	dateLayouts  []string
+	businessLine string
+}
+
+// Option настраивает сервис фильтрации
+type Option func(*service)
+
+// WithDateLayouts задает форматы, в которых может быть записан DeprecatedDate.
+// Пустой список оставляет DefaultDateLayouts.
+func WithDateLayouts(layouts []string) Option {
+	return func(s *service) {
+		if len(layouts) > 0 {
+			s.dateLayouts = layouts
+		}
+	}
 }
 
-func New(repo repository.Repository) Service {
-	return &service{repo: repo}
+// WithBusinessLine задает бизнес-линию отбираемых сервисов.
+// Пустая строка оставляет TargetBusinessLine.
+func WithBusinessLine(line string) Option {
+	return func(s *service) {
+		if line != "" {
+			s.businessLine = line
+		}
+	}
+}
+
+func New(repo repository.Repository, opts ...Option) Service {
+	s := &service{repo: repo, dateLayouts: DefaultDateLayouts, businessLine: TargetBusinessLine}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 func (s *service) FilterServices() ([]models.Result, error) {
@@ -32,7 +65,17 @@ func (s *service) FilterServices() ([]models.Result, error) {
 
 	var results []models.Result
 	for _, svc := range services {
-		if svc.DeprecatedDate == TargetDeprecatedDate && svc.BusinessLine == TargetBusinessLine {
+		if svc.BusinessLine != s.businessLine {
+			continue
+		}
+
+		deprecatedAt, err := parseDeprecatedDate(svc.DeprecatedDate, s.dateLayouts)
+		if err != nil {
+			log.Printf("⚠️ Сервис %d: %v", svc.ID, err)
+			continue
+		}
+
+		if isZeroDate(deprecatedAt) {
 			results = append(results, models.Result{
 				ID:     svc.ID,
 				Name:   svc.Name,
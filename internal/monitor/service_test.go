@@ -0,0 +1,66 @@
+package monitor
+
+import (
+	"testing"
+
+	"github.com/mdemidenko/monitoring-platform/internal/models"
+)
+
+// fakeRepository отдает заданный список сервисов
+type fakeRepository struct {
+	services []models.Service
+}
+
+func (r *fakeRepository) GetServices() ([]models.Service, error) { return r.services, nil }
+func (r *fakeRepository) SaveResults([]models.Result) error      { return nil }
+
+func TestFilterServicesZeroDates(t *testing.T) {
+	line := "line"
+	repo := &fakeRepository{services: []models.Service{
+		{ID: 1, Name: "rfc3339", BusinessLine: line, DeprecatedDate: "0001-01-01T00:00:00Z"},
+		{ID: 2, Name: "with offset", BusinessLine: line, DeprecatedDate: "0001-01-01T00:00:00+03:00"},
+		{ID: 3, Name: "no zone", BusinessLine: line, DeprecatedDate: "0001-01-01T00:00:00"},
+		{ID: 4, Name: "space", BusinessLine: line, DeprecatedDate: "0001-01-01 00:00:00"},
+		{ID: 5, Name: "date only", BusinessLine: line, DeprecatedDate: "0001-01-01"},
+		{ID: 6, Name: "empty", BusinessLine: line, DeprecatedDate: ""},
+		{ID: 7, Name: "fractional", BusinessLine: line, DeprecatedDate: "0001-01-01T00:00:00.000Z"},
+		{ID: 8, Name: "deprecated", BusinessLine: line, DeprecatedDate: "2024-05-01T00:00:00Z"},
+		{ID: 9, Name: "unknown format", BusinessLine: line, DeprecatedDate: "01.01.0001"},
+		{ID: 10, Name: "other line", BusinessLine: "other", DeprecatedDate: "0001-01-01"},
+	}}
+
+	results, err := New(repo, WithBusinessLine(line)).FilterServices()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []int
+	for _, result := range results {
+		ids = append(ids, result.ID)
+	}
+	want := []int{1, 2, 3, 4, 5, 6, 7}
+	if len(ids) != len(want) {
+		t.Fatalf("matched %v, want %v", ids, want)
+	}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Fatalf("matched %v, want %v", ids, want)
+		}
+	}
+}
+
+func TestFilterServicesDateLayouts(t *testing.T) {
+	repo := &fakeRepository{services: []models.Service{
+		{ID: 1, Name: "custom", BusinessLine: TargetBusinessLine, DeprecatedDate: "01.01.0001"},
+		{ID: 2, Name: "default layout", BusinessLine: TargetBusinessLine, DeprecatedDate: "0001-01-01"},
+	}}
+
+	results, err := New(repo, WithDateLayouts([]string{"02.01.2006"})).FilterServices()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Заданные форматы заменяют форматы по умолчанию
+	if len(results) != 1 || results[0].ID != 1 {
+		t.Errorf("results = %+v, want only the custom layout", results)
+	}
+}
@@ -0,0 +1,21 @@
+package monitor
+
+import "github.com/mdemidenko/monitoring-platform/internal/models"
+
+// SummarizeByTenant группирует результаты по тенантам с подсчетом количества.
+// Порядок результатов внутри тенанта сохраняется.
+func SummarizeByTenant(results []models.Result) models.ResultSummary {
+	summary := models.ResultSummary{
+		Total:   len(results),
+		Tenants: make(map[string]models.TenantResults),
+	}
+
+	for _, result := range results {
+		tenant := summary.Tenants[result.Tenant]
+		tenant.Count++
+		tenant.Results = append(tenant.Results, result)
+		summary.Tenants[result.Tenant] = tenant
+	}
+
+	return summary
+}
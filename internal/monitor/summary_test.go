@@ -0,0 +1,47 @@
+package monitor
+
+import (
+	"testing"
+
+	"github.com/mdemidenko/monitoring-platform/internal/models"
+)
+
+func TestSummarizeByTenant(t *testing.T) {
+	results := []models.Result{
+		{ID: 1, Name: "api", Tenant: "alpha"},
+		{ID: 2, Name: "web", Tenant: "beta"},
+		{ID: 3, Name: "db", Tenant: "alpha"},
+		{ID: 4, Name: "cache", Tenant: ""},
+		{ID: 5, Name: "queue", Tenant: "alpha"},
+	}
+
+	summary := SummarizeByTenant(results)
+	if summary.Total != len(results) {
+		t.Errorf("total = %d, want %d", summary.Total, len(results))
+	}
+	if len(summary.Tenants) != 3 {
+		t.Fatalf("tenants = %v, want 3", summary.Tenants)
+	}
+
+	wantIDs := map[string][]int{"alpha": {1, 3, 5}, "beta": {2}, "": {4}}
+	for tenant, ids := range wantIDs {
+		group := summary.Tenants[tenant]
+		if group.Count != len(ids) || len(group.Results) != len(ids) {
+			t.Errorf("tenant %q: count=%d results=%d, want %d", tenant, group.Count, len(group.Results), len(ids))
+			continue
+		}
+		// Порядок внутри тенанта совпадает с исходным
+		for i, id := range ids {
+			if group.Results[i].ID != id {
+				t.Errorf("tenant %q: result %d has id %d, want %d", tenant, i, group.Results[i].ID, id)
+			}
+		}
+	}
+}
+
+func TestSummarizeByTenantEmpty(t *testing.T) {
+	summary := SummarizeByTenant(nil)
+	if summary.Total != 0 || summary.Tenants == nil || len(summary.Tenants) != 0 {
+		t.Errorf("summary = %+v, want zero total and an empty tenant map", summary)
+	}
+}
@@ -0,0 +1,68 @@
+package monitor
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/mdemidenko/monitoring-platform/internal/models"
+)
+
+// ValidationMode поведение при обнаружении некорректных результатов
+type ValidationMode string
+
+const (
+	// ValidationOff результаты не проверяются
+	ValidationOff ValidationMode = ""
+	// ValidationDrop некорректные результаты отбрасываются
+	ValidationDrop ValidationMode = "drop"
+	// ValidationFail первый некорректный результат прерывает обработку
+	ValidationFail ValidationMode = "fail"
+)
+
+// ParseValidationMode разбирает режим проверки из строки конфигурации
+func ParseValidationMode(value string) (ValidationMode, error) {
+	switch mode := ValidationMode(value); mode {
+	case ValidationOff, ValidationDrop, ValidationFail:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("неизвестный режим проверки: %s", value)
+	}
+}
+
+// ValidateResult проверяет результат на признаки некачественных исходных данных
+func ValidateResult(result models.Result) error {
+	var errs []error
+	if result.ID == 0 {
+		errs = append(errs, errors.New("пустой id"))
+	}
+	if result.Name == "" {
+		errs = append(errs, errors.New("пустое имя"))
+	}
+	return errors.Join(errs...)
+}
+
+// ValidateResults фильтрует результаты перед сохранением согласно режиму проверки.
+// Возвращает корректные результаты и количество отклоненных.
+func ValidateResults(results []models.Result, mode ValidationMode) ([]models.Result, int, error) {
+	if mode == ValidationOff {
+		return results, 0, nil
+	}
+
+	valid := make([]models.Result, 0, len(results))
+	rejected := 0
+
+	for i, result := range results {
+		if err := ValidateResult(result); err != nil {
+			if mode == ValidationFail {
+				return nil, rejected, fmt.Errorf("некорректный результат %d (id=%d): %w", i, result.ID, err)
+			}
+			log.Printf("⚠️  Отклонен некорректный результат %d (id=%d): %v", i, result.ID, err)
+			rejected++
+			continue
+		}
+		valid = append(valid, result)
+	}
+
+	return valid, rejected, nil
+}
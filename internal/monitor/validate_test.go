@@ -0,0 +1,71 @@
+package monitor
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mdemidenko/monitoring-platform/internal/models"
+)
+
+func TestValidateResults(t *testing.T) {
+	results := []models.Result{
+		{ID: 1, Name: "api", Tenant: "a"},
+		{ID: 0, Name: "no id", Tenant: "a"},
+		{ID: 3, Name: "", Tenant: "b"},
+		{ID: 4, Name: "web", Tenant: "b"},
+		{},
+	}
+	valid := []models.Result{results[0], results[3]}
+
+	tests := []struct {
+		name         string
+		mode         ValidationMode
+		want         []models.Result
+		wantRejected int
+		wantErr      string
+	}{
+		{name: "off keeps everything", mode: ValidationOff, want: results},
+		{name: "drop", mode: ValidationDrop, want: valid, wantRejected: 3},
+		{name: "fail on the first invalid result", mode: ValidationFail, wantErr: "некорректный результат 1 (id=0): пустой id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, rejected, err := ValidateResults(results, tt.mode)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if rejected != tt.wantRejected {
+				t.Errorf("rejected = %d, want %d", rejected, tt.wantRejected)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("results = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateResultJoinsErrors(t *testing.T) {
+	err := ValidateResult(models.Result{})
+	if err == nil || !strings.Contains(err.Error(), "пустой id") || !strings.Contains(err.Error(), "пустое имя") {
+		t.Errorf("error = %v, want both problems", err)
+	}
+}
+
+func TestParseValidationMode(t *testing.T) {
+	for _, value := range []string{"", "drop", "fail"} {
+		if mode, err := ParseValidationMode(value); err != nil || string(mode) != value {
+			t.Errorf("ParseValidationMode(%q) = %q, %v", value, mode, err)
+		}
+	}
+	if _, err := ParseValidationMode("strict"); err == nil {
+		t.Error("ParseValidationMode accepted an unknown mode")
+	}
+}
@@ -0,0 +1,149 @@
+package notifier
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen возвращается, когда circuit breaker не пропускает запросы к Telegram
+var ErrCircuitOpen = errors.New("telegram circuit breaker is open")
+
+// BreakerState состояние circuit breaker
+type BreakerState int
+
+const (
+	BreakerClosed BreakerState = iota
+	BreakerOpen
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// circuitBreaker защищает Telegram от лавины запросов при его недоступности.
+// После threshold подряд идущих ошибок breaker размыкается на cooldown,
+// затем пропускает один пробный запрос. Каждая неудачная проба удваивает
+// cooldown (экспоненциальный backoff) вплоть до maxCooldown.
+type circuitBreaker struct {
+	mu sync.Mutex
+
+	threshold    int
+	baseCooldown time.Duration
+	maxCooldown  time.Duration
+
+	state    BreakerState
+	failures int
+	cooldown time.Duration
+	openedAt time.Time
+	probing  bool
+
+	now func() time.Time
+}
+
+func newCircuitBreaker(threshold int, cooldown, maxCooldown time.Duration) *circuitBreaker {
+	if maxCooldown < cooldown {
+		maxCooldown = cooldown
+	}
+	return &circuitBreaker{
+		threshold:    threshold,
+		baseCooldown: cooldown,
+		maxCooldown:  maxCooldown,
+		cooldown:     cooldown,
+		now:          time.Now,
+	}
+}
+
+// Allow проверяет, можно ли выполнить запрос
+func (b *circuitBreaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return ErrCircuitOpen
+		}
+		b.state = BreakerHalfOpen
+		b.probing = true
+		return nil
+	case BreakerHalfOpen:
+		if b.probing {
+			return ErrCircuitOpen
+		}
+		b.probing = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+// Success фиксирует успешный запрос и замыкает breaker
+func (b *circuitBreaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = BreakerClosed
+	b.failures = 0
+	b.cooldown = b.baseCooldown
+	b.probing = false
+}
+
+// Failure фиксирует неудачный запрос
+func (b *circuitBreaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerHalfOpen:
+		b.cooldown *= 2
+		if b.cooldown > b.maxCooldown {
+			b.cooldown = b.maxCooldown
+		}
+		b.trip()
+	default:
+		b.failures++
+		if b.failures >= b.threshold {
+			b.trip()
+		}
+	}
+}
+
+// Cancel фиксирует запрос, прерванный вызывающим (отмена контекста, таймаут
+// вызывающего, shutdown). Это не сбой Telegram: счетчик ошибок не растет,
+// а пробный запрос half-open освобождается без увеличения cooldown.
+func (b *circuitBreaker) Cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == BreakerHalfOpen {
+		b.probing = false
+	}
+}
+
+// State возвращает текущее состояние breaker
+func (b *circuitBreaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == BreakerOpen && b.now().Sub(b.openedAt) >= b.cooldown {
+		return BreakerHalfOpen
+	}
+	return b.state
+}
+
+func (b *circuitBreaker) trip() {
+	b.state = BreakerOpen
+	b.openedAt = b.now()
+	b.probing = false
+}
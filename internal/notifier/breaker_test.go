@@ -0,0 +1,157 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/mdemidenko/monitoring-platform/internal/models"
+)
+
+func TestCircuitBreakerTransitions(t *testing.T) {
+	type step struct {
+		// action: allow, success, failure, cancel или wait
+		action    string
+		wait      time.Duration
+		wantErr   error
+		wantState BreakerState
+	}
+
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "opens after threshold and closes after a successful probe",
+			steps: []step{
+				{action: "failure", wantState: BreakerClosed},
+				{action: "failure", wantState: BreakerOpen},
+				{action: "allow", wantErr: ErrCircuitOpen, wantState: BreakerOpen},
+				{action: "wait", wait: 10 * time.Second, wantState: BreakerHalfOpen},
+				{action: "allow", wantState: BreakerHalfOpen},
+				{action: "allow", wantErr: ErrCircuitOpen, wantState: BreakerHalfOpen},
+				{action: "success", wantState: BreakerClosed},
+				{action: "allow", wantState: BreakerClosed},
+			},
+		},
+		{
+			name: "failed probe doubles the cooldown",
+			steps: []step{
+				{action: "failure"},
+				{action: "failure", wantState: BreakerOpen},
+				{action: "wait", wait: 10 * time.Second, wantState: BreakerHalfOpen},
+				{action: "allow", wantState: BreakerHalfOpen},
+				{action: "failure", wantState: BreakerOpen},
+				{action: "wait", wait: 10 * time.Second, wantState: BreakerOpen},
+				{action: "wait", wait: 10 * time.Second, wantState: BreakerHalfOpen},
+			},
+		},
+		{
+			name: "cooldown is capped",
+			steps: []step{
+				{action: "failure"},
+				{action: "failure", wantState: BreakerOpen},
+				{action: "wait", wait: 10 * time.Second, wantState: BreakerHalfOpen},
+				{action: "allow", wantState: BreakerHalfOpen},
+				{action: "failure", wantState: BreakerOpen},
+				{action: "wait", wait: 20 * time.Second, wantState: BreakerHalfOpen},
+				{action: "allow", wantState: BreakerHalfOpen},
+				{action: "failure", wantState: BreakerOpen},
+				{action: "wait", wait: 29 * time.Second, wantState: BreakerOpen},
+				{action: "wait", wait: time.Second, wantState: BreakerHalfOpen},
+			},
+		},
+		{
+			name: "cancelled probe is released without penalty",
+			steps: []step{
+				{action: "failure"},
+				{action: "failure", wantState: BreakerOpen},
+				{action: "wait", wait: 10 * time.Second, wantState: BreakerHalfOpen},
+				{action: "allow", wantState: BreakerHalfOpen},
+				{action: "cancel", wantState: BreakerHalfOpen},
+				{action: "allow", wantState: BreakerHalfOpen},
+			},
+		},
+		{
+			name: "cancelled requests do not count as failures",
+			steps: []step{
+				{action: "cancel"},
+				{action: "cancel"},
+				{action: "failure", wantState: BreakerClosed},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+			b := newCircuitBreaker(2, 10*time.Second, 30*time.Second)
+			b.now = func() time.Time { return now }
+
+			for i, s := range tt.steps {
+				var err error
+				switch s.action {
+				case "allow":
+					err = b.Allow()
+				case "success":
+					b.Success()
+				case "failure":
+					b.Failure()
+				case "cancel":
+					b.Cancel()
+				case "wait":
+					now = now.Add(s.wait)
+				}
+
+				if !errors.Is(err, s.wantErr) {
+					t.Fatalf("step %d (%s): err = %v, want %v", i, s.action, err, s.wantErr)
+				}
+				if got := b.State(); got != s.wantState {
+					t.Fatalf("step %d (%s): state = %s, want %s", i, s.action, got, s.wantState)
+				}
+			}
+		})
+	}
+}
+
+func TestCancelledSendDoesNotTripBreaker(t *testing.T) {
+	cfg := testConfig()
+	cfg.Telegram.BreakerThreshold = 1
+	fake := &fakeTelegram{delay: time.Minute}
+	svc, _ := newTestService(t, cfg, fake)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := svc.SendMessage(ctx, models.NewNotification("", "alert")); err == nil {
+		t.Fatal("expected an error from the cancelled send")
+	}
+
+	if state := svc.breaker.State(); state != BreakerClosed {
+		t.Errorf("breaker state = %s after cancellation, want closed", state)
+	}
+}
+
+func TestNetworkErrorTripsBreaker(t *testing.T) {
+	cfg := testConfig()
+	cfg.Telegram.BreakerThreshold = 1
+	svc, _ := newTestService(t, cfg, &fakeTelegram{})
+	svc.client.Transport = roundTripFunc(func(*http.Request) (*http.Response, error) {
+		return nil, errors.New("connection refused")
+	})
+
+	if _, err := svc.SendMessage(context.Background(), models.NewNotification("", "alert")); err == nil {
+		t.Fatal("expected a network error")
+	}
+
+	if state := svc.breaker.State(); state != BreakerOpen {
+		t.Errorf("breaker state = %s after a network error, want open", state)
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
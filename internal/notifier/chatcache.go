@@ -0,0 +1,121 @@
+package notifier
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mdemidenko/monitoring-platform/config"
+	"github.com/mdemidenko/monitoring-platform/internal/models"
+)
+
+// chatCache LRU кэш метаданных чатов с ограниченным временем жизни записей.
+// При переполнении вытесняется запись, к которой дольше всего не обращались.
+type chatCache struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	order   *list.List
+	entries map[string]*list.Element
+
+	now func() time.Time
+}
+
+type chatCacheEntry struct {
+	chatID    string
+	chat      *models.Chat
+	expiresAt time.Time
+}
+
+func newChatCache(size int, ttl time.Duration) *chatCache {
+	return &chatCache{
+		size:    size,
+		ttl:     ttl,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+		now:     time.Now,
+	}
+}
+
+// newChatCacheFromConfig создает кэш по настройкам из конфигурации
+func newChatCacheFromConfig(cfg config.TelegramConfig) *chatCache {
+	size := defaultChatCacheSize
+	if cfg.ChatCacheSize > 0 {
+		size = cfg.ChatCacheSize
+	}
+
+	ttl := defaultChatCacheTTL
+	if cfg.ChatCacheTTL > 0 {
+		ttl = time.Duration(cfg.ChatCacheTTL) * time.Second
+	}
+
+	return newChatCache(size, ttl)
+}
+
+// get возвращает чат из кэша, если запись есть и не устарела
+func (c *chatCache) get(chatID string) (*models.Chat, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[chatID]
+	if !ok {
+		return nil, false
+	}
+
+	entry := elem.Value.(*chatCacheEntry)
+	if !c.now().Before(entry.expiresAt) {
+		c.order.Remove(elem)
+		delete(c.entries, chatID)
+		return nil, false
+	}
+
+	c.order.MoveToFront(elem)
+	return entry.chat, true
+}
+
+// put сохраняет чат в кэше, вытесняя самую старую запись при переполнении
+func (c *chatCache) put(chatID string, chat *models.Chat) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expiresAt := c.now().Add(c.ttl)
+	if elem, ok := c.entries[chatID]; ok {
+		entry := elem.Value.(*chatCacheEntry)
+		entry.chat = chat
+		entry.expiresAt = expiresAt
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries[chatID] = c.order.PushFront(&chatCacheEntry{chatID: chatID, chat: chat, expiresAt: expiresAt})
+
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*chatCacheEntry).chatID)
+	}
+}
+
+// GetChat возвращает метаданные чата. Повторные запросы в пределах
+// chat_cache_ttl обслуживаются из кэша без обращения к Telegram.
+func (s *TelegramService) GetChat(ctx context.Context, chatID string) (*models.Chat, error) {
+	if chat, ok := s.chats.get(chatID); ok {
+		return chat, nil
+	}
+
+	result, err := s.callAPI(ctx, s.Config().Telegram.BotToken, "getChat", map[string]string{"chat_id": chatID})
+	if err != nil {
+		return nil, err
+	}
+
+	var chat models.Chat
+	if err := json.Unmarshal(result, &chat); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal chat: %w", err)
+	}
+
+	s.chats.put(chatID, &chat)
+	return &chat, nil
+}
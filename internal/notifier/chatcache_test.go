@@ -0,0 +1,54 @@
+package notifier
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestGetChatCache(t *testing.T) {
+	cfg := testConfig()
+	cfg.Telegram.ChatCacheSize = 2
+	cfg.Telegram.ChatCacheTTL = 60
+	fake := &fakeTelegram{}
+	svc, _ := newTestService(t, cfg, fake)
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	svc.chats.now = func() time.Time { return now }
+
+	getChat := func(chatID string) {
+		t.Helper()
+		chat, err := svc.GetChat(context.Background(), chatID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if chat.Title != "chat "+chatID {
+			t.Fatalf("GetChat(%s) = %+v", chatID, chat)
+		}
+	}
+
+	steps := []struct {
+		name string
+		// advance сдвигает часы перед запросом
+		advance   time.Duration
+		chatID    string
+		wantCalls int
+	}{
+		{name: "first request", chatID: "100", wantCalls: 1},
+		{name: "cache hit", advance: 30 * time.Second, chatID: "100", wantCalls: 1},
+		{name: "another chat", chatID: "200", wantCalls: 2},
+		{name: "expired entry", advance: 31 * time.Second, chatID: "100", wantCalls: 3},
+		{name: "unexpired entry", chatID: "200", wantCalls: 3},
+		{name: "third chat evicts the least recently used", chatID: "300", wantCalls: 4},
+		{name: "evicted chat", chatID: "100", wantCalls: 5},
+		{name: "recently used chat kept", chatID: "300", wantCalls: 5},
+	}
+
+	for _, step := range steps {
+		now = now.Add(step.advance)
+		getChat(step.chatID)
+		if calls := len(fake.calls()); calls != step.wantCalls {
+			t.Fatalf("%s: %d getChat calls, want %d", step.name, calls, step.wantCalls)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package notifier
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/mdemidenko/monitoring-platform/internal/models"
+)
+
+// DeadLetter запись dead-letter файла: уведомление, которое не удалось отправить
+type DeadLetter struct {
+	Notification *models.Notification `json:"notification"`
+	Error        string               `json:"error"`
+	FailedAt     time.Time            `json:"failed_at"`
+}
+
+// deadLetterFile дописывает неотправленные уведомления в JSONL файл.
+// Запись и чтение сериализуются мьютексом, поэтому строки от разных
+// worker'ов не перемешиваются.
+type deadLetterFile struct {
+	mu sync.Mutex
+}
+
+// append дописывает запись одной строкой
+func (d *deadLetterFile) append(path string, letter DeadLetter) error {
+	line, err := json.Marshal(letter)
+	if err != nil {
+		return fmt.Errorf("failed to marshal dead letter: %w", err)
+	}
+	line = append(line, '\n')
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open dead letter file: %w", err)
+	}
+	_, writeErr := file.Write(line)
+	closeErr := file.Close()
+
+	return errors.Join(writeErr, closeErr)
+}
+
+// read читает все записи и возвращает их вместе с размером прочитанной
+// части файла: все, что дописано после нее, появилось уже после чтения
+func (d *deadLetterFile) read(path string) ([]DeadLetter, int64, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read dead letter file: %w", err)
+	}
+
+	var letters []DeadLetter
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var letter DeadLetter
+		if err := json.Unmarshal(scanner.Bytes(), &letter); err != nil || letter.Notification == nil {
+			return nil, 0, fmt.Errorf("invalid dead letter on line %d: %v", line, err)
+		}
+		letters = append(letters, letter)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to read dead letter file: %w", err)
+	}
+
+	return letters, int64(len(data)), nil
+}
+
+// replace заменяет первые offset байт файла записями keep, сохраняя все,
+// что было дописано после чтения. Файл подменяется через os.Rename, поэтому
+// при сбое остается либо старое, либо новое содержимое.
+func (d *deadLetterFile) replace(path string, offset int64, keep []DeadLetter) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to read dead letter file: %w", err)
+	}
+
+	var buf bytes.Buffer
+	for _, letter := range keep {
+		line, err := json.Marshal(letter)
+		if err != nil {
+			return fmt.Errorf("failed to marshal dead letter: %w", err)
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	if offset < int64(len(data)) {
+		buf.Write(data[offset:])
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create dead letter file: %w", err)
+	}
+	_, writeErr := tmp.Write(buf.Bytes())
+	closeErr := tmp.Close()
+	if err := errors.Join(writeErr, closeErr); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write dead letter file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to replace dead letter file: %w", err)
+	}
+
+	return nil
+}
+
+// deadLetter сохраняет уведомление в dead-letter файл, если отправка
+// окончательно не удалась и файл задан в конфигурации
+func (s *TelegramService) deadLetter(notification *models.Notification, err error) {
+	path := s.Config().Telegram.DeadLetterFile
+	if path == "" || err == nil ||
+		errors.Is(err, ErrDuplicate) || errors.Is(err, ErrQueued) || errors.Is(err, ErrDeferred) || errors.Is(err, ErrNoResult) {
+		return
+	}
+
+	letter := DeadLetter{Notification: notification, Error: err.Error(), FailedAt: time.Now()}
+	if err := s.deadLetters.append(path, letter); err != nil {
+		log.Printf("❌ Не удалось записать уведомление %s в dead-letter файл: %v", notification.ID, err)
+		return
+	}
+	log.Printf("📮 Уведомление %s записано в dead-letter файл %s", notification.ID, path)
+}
+
+// ReplayDeadLetters повторно отправляет уведомления из dead-letter файла.
+// Уведомления, которые снова не удалось отправить, дописываются в файл заново.
+// Прочитанные записи удаляются из файла только после повторной отправки,
+// поэтому сбой посреди нее не теряет записи (они могут быть отправлены повторно).
+func (s *TelegramService) ReplayDeadLetters(ctx context.Context) (ProcessResult, error) {
+	path := s.Config().Telegram.DeadLetterFile
+	if path == "" {
+		return ProcessResult{}, fmt.Errorf("telegram.dead_letter_file is not configured")
+	}
+
+	letters, offset, err := s.deadLetters.read(path)
+	if err != nil {
+		return ProcessResult{}, err
+	}
+
+	log.Printf("📮 Повторная отправка из dead-letter файла: %d", len(letters))
+
+	var result ProcessResult
+	for i, letter := range letters {
+		if ctx.Err() != nil {
+			// Неотправленные записи остаются в файле
+			if err := s.deadLetters.replace(path, offset, letters[i:]); err != nil {
+				return result, err
+			}
+			return result, fmt.Errorf("replay cancelled: %w", ctx.Err())
+		}
+		s.resend(ctx, letter.Notification, &result)
+	}
+
+	if len(letters) > 0 {
+		if err := s.deadLetters.replace(path, offset, nil); err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,101 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/mdemidenko/monitoring-platform/internal/models"
+)
+
+func TestReplayDeadLetters(t *testing.T) {
+	tests := []struct {
+		name string
+		// cancel отменяет контекст до начала повторной отправки
+		cancel      bool
+		wantSuccess int
+		wantErrors  int
+		wantLeft    []string
+	}{
+		{name: "replays and keeps only new failures", wantSuccess: 2, wantErrors: 1, wantLeft: []string{"bad"}},
+		{name: "cancelled replay keeps every entry", cancel: true, wantLeft: []string{"first", "bad", "second"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			cfg.Telegram.DeadLetterFile = filepath.Join(t.TempDir(), "dead.jsonl")
+			fake := &fakeTelegram{fail: func(payload map[string]any) string {
+				if payload["text"] == "bad" {
+					return "Bad Request: chat not found"
+				}
+				return ""
+			}}
+			svc, _ := newTestService(t, cfg, fake)
+
+			for _, text := range []string{"first", "bad", "second"} {
+				svc.deadLetter(models.NewNotification("", text), errors.New("network down"))
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			if tt.cancel {
+				cancel()
+			}
+			defer cancel()
+
+			result, err := svc.ReplayDeadLetters(ctx)
+			if tt.cancel != (err != nil) {
+				t.Fatalf("ReplayDeadLetters error = %v", err)
+			}
+			if result.SuccessCount != tt.wantSuccess || result.ErrorCount != tt.wantErrors {
+				t.Errorf("success=%d errors=%d, want %d and %d",
+					result.SuccessCount, result.ErrorCount, tt.wantSuccess, tt.wantErrors)
+			}
+
+			letters, _, err := svc.deadLetters.read(cfg.Telegram.DeadLetterFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var left []string
+			for _, letter := range letters {
+				left = append(left, letter.Notification.Text)
+			}
+			if len(left) != len(tt.wantLeft) {
+				t.Fatalf("entries left = %v, want %v", left, tt.wantLeft)
+			}
+			for i := range left {
+				if left[i] != tt.wantLeft[i] {
+					t.Errorf("entries left = %v, want %v", left, tt.wantLeft)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestReplayDeadLettersKeepsConcurrentAppends(t *testing.T) {
+	cfg := testConfig()
+	cfg.Telegram.DeadLetterFile = filepath.Join(t.TempDir(), "dead.jsonl")
+	svc, _ := newTestService(t, cfg, &fakeTelegram{})
+	svc.deadLetter(models.NewNotification("", "old"), errors.New("network down"))
+
+	letters, offset, err := svc.deadLetters.read(cfg.Telegram.DeadLetterFile)
+	if err != nil || len(letters) != 1 {
+		t.Fatalf("read: %v, %d letters", err, len(letters))
+	}
+	// Запись, появившаяся во время повторной отправки
+	svc.deadLetter(models.NewNotification("", "new"), errors.New("network down"))
+
+	if err := svc.deadLetters.replace(cfg.Telegram.DeadLetterFile, offset, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	letters, _, err = svc.deadLetters.read(cfg.Telegram.DeadLetterFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(letters) != 1 || letters[0].Notification.Text != "new" {
+		t.Errorf("letters after replay = %+v, want only the new entry", letters)
+	}
+}
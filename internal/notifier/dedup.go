@@ -0,0 +1,60 @@
+package notifier
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrDuplicate возвращается, когда такое же уведомление в тот же чат уже
+// отправлялось в пределах telegram.dedup_window
+var ErrDuplicate = errors.New("duplicate notification suppressed")
+
+// dedupKey идентифицирует уведомление для подавления повторов
+type dedupKey struct {
+	chatID string
+	text   string
+}
+
+// deduplicator запоминает недавно отправленные уведомления
+type deduplicator struct {
+	mu   sync.Mutex
+	seen map[dedupKey]time.Time
+
+	now func() time.Time
+}
+
+func newDeduplicator() *deduplicator {
+	return &deduplicator{
+		seen: make(map[dedupKey]time.Time),
+		now:  time.Now,
+	}
+}
+
+// reserve отмечает уведомление как отправляемое и возвращает false, если
+// такое же уже было в пределах window. Устаревшие записи удаляются здесь же.
+func (d *deduplicator) reserve(key dedupKey, window time.Duration) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.now()
+	for k, at := range d.seen {
+		if now.Sub(at) >= window {
+			delete(d.seen, k)
+		}
+	}
+
+	if _, ok := d.seen[key]; ok {
+		return false
+	}
+	d.seen[key] = now
+	return true
+}
+
+// release снимает отметку, если отправка не удалась, чтобы повтор не был подавлен
+func (d *deduplicator) release(key dedupKey) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	delete(d.seen, key)
+}
@@ -0,0 +1,79 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mdemidenko/monitoring-platform/internal/models"
+)
+
+func TestSendMessageDedupWindow(t *testing.T) {
+	cfg := testConfig()
+	cfg.Telegram.DedupWindow = 60
+	fake := &fakeTelegram{fail: func(payload map[string]any) string {
+		if payload["text"] == "flaky" && payload["chat_id"] == "500" {
+			return "Bad Request: chat not found"
+		}
+		return ""
+	}}
+	svc, _ := newTestService(t, cfg, fake)
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	svc.dedup.now = func() time.Time { return now }
+
+	steps := []struct {
+		name    string
+		advance time.Duration
+		chatID  string
+		text    string
+		wantErr error
+		// wantSent уведомление дошло до Telegram (успешно или с ошибкой API)
+		wantSent bool
+	}{
+		{name: "first send", chatID: "100", text: "disk full", wantSent: true},
+		{name: "repeat within the window", advance: 30 * time.Second, chatID: "100", text: "disk full", wantErr: ErrDuplicate},
+		{name: "same text to another chat", chatID: "200", text: "disk full", wantSent: true},
+		{name: "other text to the same chat", chatID: "100", text: "disk ok", wantSent: true},
+		{name: "repeat after the window", advance: 30 * time.Second, chatID: "100", text: "disk full", wantSent: true},
+		{name: "failed send", chatID: "500", text: "flaky", wantErr: ErrChatNotFound, wantSent: true},
+		{name: "retry after a failure is not suppressed", chatID: "500", text: "flaky", wantErr: ErrChatNotFound, wantSent: true},
+	}
+
+	for _, step := range steps {
+		now = now.Add(step.advance)
+		before := len(fake.calls())
+
+		_, err := svc.SendMessage(context.Background(), models.NewNotification(step.chatID, step.text))
+		if step.wantErr == nil && err != nil || step.wantErr != nil && !errors.Is(err, step.wantErr) {
+			t.Errorf("%s: error = %v, want %v", step.name, err, step.wantErr)
+		}
+		if sent := len(fake.calls()) > before; sent != step.wantSent {
+			t.Errorf("%s: sent = %v, want %v", step.name, sent, step.wantSent)
+		}
+	}
+}
+
+func TestProcessWithIntervalsDedup(t *testing.T) {
+	cfg := testConfig()
+	cfg.Telegram.DedupWindow = 60
+	fake := &fakeTelegram{}
+	svc, _ := newTestService(t, cfg, fake)
+
+	notifications := []*models.Notification{
+		models.NewNotification("", "disk full"),
+		models.NewNotification("", "disk full"),
+		models.NewNotification("", "disk ok"),
+	}
+	result := svc.ProcessWithIntervals(context.Background(), notifications, 0, 1)
+	if result.SuccessCount != 2 || result.SuppressedCount != 1 || result.ErrorCount != 0 {
+		t.Errorf("success=%d suppressed=%d errors=%d, want 2, 1 and 0", result.SuccessCount, result.SuppressedCount, result.ErrorCount)
+	}
+	if len(fake.calls()) != 2 {
+		t.Errorf("made %d Telegram calls, want 2", len(fake.calls()))
+	}
+	if stats := svc.Stats(); stats.Suppressed != 1 {
+		t.Errorf("Stats().Suppressed = %d, want 1", stats.Suppressed)
+	}
+}
@@ -0,0 +1,120 @@
+package notifier
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Ошибки взаимодействия с Telegram. Возвращаемые сервисом ошибки оборачивают
+// их, поэтому вызывающий код может проверять категорию через errors.Is.
+var (
+	// ErrRateLimited Telegram ограничил частоту запросов (429)
+	ErrRateLimited = errors.New("telegram rate limit exceeded")
+	// ErrInvalidToken токен бота отклонен (401)
+	ErrInvalidToken = errors.New("telegram bot token is invalid")
+	// ErrChatNotFound чат не существует или бот не имеет к нему доступа
+	ErrChatNotFound = errors.New("telegram chat not found")
+	// ErrReplyNotFound сообщение, на которое отправляется ответ, не существует
+	ErrReplyNotFound = errors.New("telegram message to reply to not found")
+	// ErrNetwork запрос не дошел до Telegram или ответ не был получен
+	ErrNetwork = errors.New("telegram network error")
+	// ErrResponseTooLarge ответ Bot API превышает telegram.max_response_size
+	ErrResponseTooLarge = errors.New("telegram response too large")
+	// ErrNoResult Telegram принял запрос (ok=true), но не вернул объект сообщения
+	ErrNoResult = errors.New("telegram accepted the message but returned no result")
+)
+
+// APIError ошибка, возвращенная Telegram Bot API (ok=false)
+type APIError struct {
+	Code        int
+	Description string
+	// RetryAfter через сколько можно повторить запрос (для 429)
+	RetryAfter time.Duration
+
+	kind error
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("telegram API error %d: %s", e.Code, e.Description)
+}
+
+// Unwrap возвращает категорию ошибки (ErrRateLimited, ErrInvalidToken, ...)
+func (e *APIError) Unwrap() error {
+	return e.kind
+}
+
+// newAPIError создает APIError и определяет ее категорию по коду и описанию
+func newAPIError(code int, description string, retryAfter int) *APIError {
+	apiErr := &APIError{
+		Code:        code,
+		Description: description,
+		RetryAfter:  time.Duration(retryAfter) * time.Second,
+	}
+
+	switch {
+	case code == http.StatusTooManyRequests:
+		apiErr.kind = ErrRateLimited
+	case code == http.StatusUnauthorized:
+		apiErr.kind = ErrInvalidToken
+	case strings.Contains(strings.ToLower(description), "chat not found"):
+		apiErr.kind = ErrChatNotFound
+	case strings.Contains(strings.ToLower(description), "message to be replied not found"):
+		apiErr.kind = ErrReplyNotFound
+	}
+
+	return apiErr
+}
+
+// networkError оборачивает ошибку транспорта в ErrNetwork
+func networkError(op string, err error) error {
+	return fmt.Errorf("%s: %w: %w", op, ErrNetwork, err)
+}
+
+// ErrorCategory категория ошибки отправки, по которой клиент решает, повторять ли запрос
+type ErrorCategory string
+
+const (
+	CategoryRateLimited  ErrorCategory = "rate_limited"
+	CategoryInvalidChat  ErrorCategory = "invalid_chat"
+	CategoryInvalidReply ErrorCategory = "invalid_reply"
+	CategoryInvalidToken ErrorCategory = "invalid_token"
+	CategoryNetwork      ErrorCategory = "network"
+	CategoryCircuitOpen  ErrorCategory = "circuit_open"
+	CategoryOther        ErrorCategory = "other"
+)
+
+// Categorize определяет категорию ошибки по sentinel-ошибкам сервиса;
+// для nil возвращает пустую категорию
+func Categorize(err error) ErrorCategory {
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, ErrRateLimited):
+		return CategoryRateLimited
+	case errors.Is(err, ErrChatNotFound):
+		return CategoryInvalidChat
+	case errors.Is(err, ErrReplyNotFound):
+		return CategoryInvalidReply
+	case errors.Is(err, ErrInvalidToken):
+		return CategoryInvalidToken
+	case errors.Is(err, ErrNetwork):
+		return CategoryNetwork
+	case errors.Is(err, ErrCircuitOpen):
+		return CategoryCircuitOpen
+	default:
+		return CategoryOther
+	}
+}
+
+// Retryable сообщает, имеет ли смысл повторить отправку позже
+func (c ErrorCategory) Retryable() bool {
+	switch c {
+	case CategoryRateLimited, CategoryNetwork, CategoryCircuitOpen:
+		return true
+	default:
+		return false
+	}
+}
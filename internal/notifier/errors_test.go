@@ -0,0 +1,163 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mdemidenko/monitoring-platform/internal/models"
+	"github.com/mdemidenko/monitoring-platform/internal/repository"
+)
+
+func TestSendErrorKinds(t *testing.T) {
+	apiError := func(status int, body map[string]any) roundTripFunc {
+		return func(*http.Request) (*http.Response, error) {
+			return jsonResponse(status, body), nil
+		}
+	}
+
+	tests := []struct {
+		name           string
+		transport      roundTripFunc
+		want           error
+		wantCategory   ErrorCategory
+		wantRetryAfter time.Duration
+	}{
+		{
+			name: "rate limited",
+			transport: apiError(http.StatusTooManyRequests, map[string]any{
+				"ok": false, "error_code": 429, "description": "Too Many Requests: retry after 5",
+				"parameters": map[string]any{"retry_after": 5},
+			}),
+			want:           ErrRateLimited,
+			wantCategory:   CategoryRateLimited,
+			wantRetryAfter: 5 * time.Second,
+		},
+		{
+			name:         "chat not found",
+			transport:    apiError(http.StatusBadRequest, map[string]any{"ok": false, "error_code": 400, "description": "Bad Request: chat not found"}),
+			want:         ErrChatNotFound,
+			wantCategory: CategoryInvalidChat,
+		},
+		{
+			name:         "reply not found",
+			transport:    apiError(http.StatusBadRequest, map[string]any{"ok": false, "error_code": 400, "description": "Bad Request: message to be replied not found"}),
+			want:         ErrReplyNotFound,
+			wantCategory: CategoryInvalidReply,
+		},
+		{
+			name:         "invalid token",
+			transport:    apiError(http.StatusUnauthorized, map[string]any{"ok": false, "error_code": 401, "description": "Unauthorized"}),
+			want:         ErrInvalidToken,
+			wantCategory: CategoryInvalidToken,
+		},
+		{
+			name: "network",
+			transport: func(*http.Request) (*http.Response, error) {
+				return nil, errors.New("connection refused")
+			},
+			want:         ErrNetwork,
+			wantCategory: CategoryNetwork,
+		},
+	}
+
+	sentinels := []error{ErrRateLimited, ErrChatNotFound, ErrReplyNotFound, ErrInvalidToken, ErrNetwork}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewTelegramService(testConfig(), repository.NewMemoryStorage(), WithTransport(tt.transport))
+
+			_, err := svc.SendMessage(context.Background(), models.NewNotification("", "alert"))
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("error = %v, want %v", err, tt.want)
+			}
+			for _, other := range sentinels {
+				if other != tt.want && errors.Is(err, other) {
+					t.Errorf("error %v also matches %v", err, other)
+				}
+			}
+			if category := Categorize(err); category != tt.wantCategory {
+				t.Errorf("Categorize = %q, want %q", category, tt.wantCategory)
+			}
+
+			var apiErr *APIError
+			if errors.As(err, &apiErr) {
+				if apiErr.RetryAfter != tt.wantRetryAfter {
+					t.Errorf("RetryAfter = %v, want %v", apiErr.RetryAfter, tt.wantRetryAfter)
+				}
+			} else if tt.want != ErrNetwork {
+				t.Errorf("error %v is not an *APIError", err)
+			}
+		})
+	}
+}
+
+func TestProcessWithIntervalsErrorCategories(t *testing.T) {
+	// Текст уведомления задает ответ Bot API
+	failures := map[string]struct {
+		status      int
+		description string
+	}{
+		"rate":  {http.StatusTooManyRequests, "Too Many Requests: retry after 1"},
+		"chat":  {http.StatusBadRequest, "Bad Request: chat not found"},
+		"reply": {http.StatusBadRequest, "Bad Request: message to be replied not found"},
+		"token": {http.StatusUnauthorized, "Unauthorized"},
+		"other": {http.StatusBadRequest, "Bad Request: message text is empty"},
+	}
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		var payload map[string]any
+		if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+			return nil, err
+		}
+		text := payload["text"].(string)
+		if text == "network" {
+			return nil, errors.New("connection reset")
+		}
+		if failure, ok := failures[text]; ok {
+			return jsonResponse(failure.status, map[string]any{
+				"ok": false, "error_code": failure.status, "description": failure.description,
+			}), nil
+		}
+		return jsonResponse(http.StatusOK, map[string]any{
+			"ok": true, "result": map[string]any{"message_id": 1, "chat": map[string]any{"id": 100}},
+		}), nil
+	})
+	cfg := testConfig()
+	// Ошибки не должны размыкать выключатель посреди пакета
+	cfg.Telegram.BreakerThreshold = 100
+	svc := NewTelegramService(cfg, repository.NewMemoryStorage(), WithTransport(transport))
+
+	texts := []string{"ok", "rate", "chat", "chat", "reply", "token", "network", "other", "ok"}
+	var notifications []*models.Notification
+	for _, text := range texts {
+		notifications = append(notifications, models.NewNotification("", text))
+	}
+	result := svc.ProcessWithIntervals(context.Background(), notifications, 0, 2)
+
+	want := map[ErrorCategory]int{
+		CategoryRateLimited:  1,
+		CategoryInvalidChat:  2,
+		CategoryInvalidReply: 1,
+		CategoryInvalidToken: 1,
+		CategoryNetwork:      1,
+		CategoryOther:        1,
+	}
+	if !reflect.DeepEqual(result.ErrorCategories, want) {
+		t.Errorf("ErrorCategories = %v, want %v", result.ErrorCategories, want)
+	}
+	if result.SuccessCount != 2 || result.ErrorCount != 7 {
+		t.Errorf("success=%d errors=%d, want 2 and 7", result.SuccessCount, result.ErrorCount)
+	}
+	for i, res := range result.Results {
+		if res.Success != (texts[i] == "ok") || res.Success && res.Category != "" {
+			t.Errorf("result %d = %+v", i, res)
+		}
+	}
+	if CategoryInvalidReply.Retryable() {
+		t.Error("invalid_reply reported as retryable")
+	}
+}
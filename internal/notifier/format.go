@@ -0,0 +1,33 @@
+package notifier
+
+import (
+	"html"
+	"strings"
+)
+
+// Режимы разметки Telegram
+const (
+	ParseModeHTML       = "HTML"
+	ParseModeMarkdown   = "Markdown"
+	ParseModeMarkdownV2 = "MarkdownV2"
+)
+
+// markdownV2Escaper экранирует спецсимволы MarkdownV2 согласно документации Bot API
+var markdownV2Escaper = strings.NewReplacer(
+	`\`, `\\`,
+	"_", `\_`, "*", `\*`, "[", `\[`, "]", `\]`, "(", `\(`, ")", `\)`,
+	"~", `\~`, "`", "\\`", ">", `\>`, "#", `\#`, "+", `\+`, "-", `\-`,
+	"=", `\=`, "|", `\|`, "{", `\{`, "}", `\}`, ".", `\.`, "!", `\!`,
+)
+
+// escapeForParseMode экранирует простой текст для вставки в сообщение с указанной разметкой
+func escapeForParseMode(text, parseMode string) string {
+	switch parseMode {
+	case ParseModeMarkdownV2:
+		return markdownV2Escaper.Replace(text)
+	case ParseModeHTML:
+		return html.EscapeString(text)
+	default:
+		return text
+	}
+}
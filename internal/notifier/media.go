@@ -0,0 +1,139 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mdemidenko/monitoring-platform/internal/models"
+)
+
+// Ограничения Telegram Bot API на размер загружаемых файлов
+const (
+	MaxPhotoSize    = 10 << 20
+	MaxDocumentSize = 50 << 20
+)
+
+// SendPhoto отправляет изображение в чат. photo может быть URL (Telegram
+// загрузит его сам) или путем к локальному файлу, который загружается
+// через multipart/form-data. Пустой chatID означает чат по умолчанию.
+func (s *TelegramService) SendPhoto(ctx context.Context, chatID, photo, caption string) (*models.SentNotification, error) {
+	return s.sendMedia(ctx, "sendPhoto", "photo", chatID, photo, caption, MaxPhotoSize)
+}
+
+// SendDocument отправляет файл в чат. document может быть URL или путем
+// к локальному файлу. Пустой chatID означает чат по умолчанию.
+func (s *TelegramService) SendDocument(ctx context.Context, chatID, document, caption string) (*models.SentNotification, error) {
+	return s.sendMedia(ctx, "sendDocument", "document", chatID, document, caption, MaxDocumentSize)
+}
+
+// sendMedia выполняет sendPhoto/sendDocument для URL или локального файла
+func (s *TelegramService) sendMedia(ctx context.Context, method, field, chatID, source, caption string, maxSize int64) (*models.SentNotification, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("operation cancelled: %w", err)
+	}
+
+	cfg := s.Config()
+	chatID = resolveChatID(cfg, chatID, cfg.Telegram.ChatID)
+
+	params := map[string]string{
+		"chat_id": chatID,
+		field:     source,
+	}
+	if caption != "" {
+		params["caption"] = caption
+		if cfg.Telegram.DefaultParseMode != "" {
+			params["parse_mode"] = cfg.Telegram.DefaultParseMode
+		}
+	}
+
+	var (
+		result json.RawMessage
+		err    error
+	)
+	if isRemoteFile(source) {
+		result, err = s.callAPI(ctx, cfg.Telegram.BotToken, method, params)
+	} else {
+		delete(params, field)
+		result, err = s.upload(ctx, cfg.Telegram.BotToken, method, field, source, maxSize, params)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeSentMessage(result)
+}
+
+// upload загружает локальный файл методом multipart/form-data.
+// Тело запроса формируется потоково, файл не читается в память целиком.
+func (s *TelegramService) upload(ctx context.Context, token, method, field, path string, maxSize int64, params map[string]string) (json.RawMessage, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("%s is a directory", path)
+	}
+	if info.Size() > maxSize {
+		return nil, fmt.Errorf("%s is %d bytes, telegram limit for %s is %d bytes", path, info.Size(), method, maxSize)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	body, writer := io.Pipe()
+	form := multipart.NewWriter(writer)
+
+	go func() {
+		writer.CloseWithError(writeMultipart(form, field, filepath.Base(path), file, params))
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, "POST", s.methodURL(token, method), body)
+	if err != nil {
+		body.Close()
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+
+	if s.Config().Telegram.Debug {
+		log.Printf("Calling POST %s: uploading %s (%d bytes)", redactURL(req.URL.String()), path, info.Size())
+	}
+
+	result, err := s.do(ctx, req)
+	body.Close()
+	return result, err
+}
+
+// writeMultipart записывает поля формы и содержимое файла
+func writeMultipart(form *multipart.Writer, field, filename string, file io.Reader, params map[string]string) error {
+	for name, value := range params {
+		if err := form.WriteField(name, value); err != nil {
+			return err
+		}
+	}
+
+	part, err := form.CreateFormFile(field, filename)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(part, file); err != nil {
+		return fmt.Errorf("failed to read %s: %w", filename, err)
+	}
+
+	return form.Close()
+}
+
+// isRemoteFile сообщает, что источник файла — URL, а не локальный путь
+func isRemoteFile(source string) bool {
+	return strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")
+}
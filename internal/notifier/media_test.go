@@ -0,0 +1,129 @@
+package notifier
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mdemidenko/monitoring-platform/internal/repository"
+)
+
+// capturedUpload содержимое multipart/form-data запроса
+type capturedUpload struct {
+	method   string
+	fields   map[string]string
+	field    string
+	filename string
+	content  string
+}
+
+// uploadTransport разбирает загрузки файлов и отвечает как sendPhoto/sendDocument
+func uploadTransport(t *testing.T, uploads *[]capturedUpload) roundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		if err := req.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("not a multipart request: %v", err)
+			return jsonResponse(http.StatusBadRequest, map[string]any{"ok": false}), nil
+		}
+
+		upload := capturedUpload{method: filepath.Base(req.URL.Path), fields: make(map[string]string)}
+		for key, values := range req.MultipartForm.Value {
+			upload.fields[key] = values[0]
+		}
+		for field, files := range req.MultipartForm.File {
+			upload.field, upload.filename = field, files[0].Filename
+			file, err := files[0].Open()
+			if err != nil {
+				return nil, err
+			}
+			data, _ := io.ReadAll(file)
+			file.Close()
+			upload.content = string(data)
+		}
+		*uploads = append(*uploads, upload)
+
+		return jsonResponse(http.StatusOK, map[string]any{"ok": true, "result": map[string]any{"message_id": 9, "chat": map[string]any{"id": 100}}}), nil
+	}
+}
+
+func TestSendMediaUpload(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "report.pdf")
+	writeFile(t, path, "%PDF report")
+
+	var uploads []capturedUpload
+	cfg := testConfig()
+	cfg.Telegram.DefaultParseMode = ParseModeHTML
+	svc := NewTelegramService(cfg, repository.NewMemoryStorage(), WithTransport(uploadTransport(t, &uploads)))
+
+	sent, err := svc.SendDocument(context.Background(), "200", path, "<b>daily</b> report")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sent.MessageID != 9 {
+		t.Errorf("sent = %+v", sent)
+	}
+	if len(uploads) != 1 {
+		t.Fatalf("got %d uploads, want 1", len(uploads))
+	}
+
+	upload := uploads[0]
+	if upload.method != "sendDocument" || upload.field != "document" || upload.filename != "report.pdf" || upload.content != "%PDF report" {
+		t.Errorf("upload = %+v", upload)
+	}
+	wantFields := map[string]string{"chat_id": "200", "caption": "<b>daily</b> report", "parse_mode": ParseModeHTML}
+	if len(upload.fields) != len(wantFields) {
+		t.Errorf("form fields = %v, want %v", upload.fields, wantFields)
+	}
+	for key, want := range wantFields {
+		if upload.fields[key] != want {
+			t.Errorf("form field %s = %q, want %q", key, upload.fields[key], want)
+		}
+	}
+}
+
+func TestSendMediaByURL(t *testing.T) {
+	fake := &fakeTelegram{}
+	svc, _ := newTestService(t, testConfig(), fake)
+
+	if _, err := svc.SendPhoto(context.Background(), "", "https://example.com/graph.png", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	req := fake.received()[0]
+	// URL передается Telegram как есть, без загрузки файла
+	if req.Method != "sendPhoto" || req.Payload["photo"] != "https://example.com/graph.png" || req.Payload["chat_id"] != "100" {
+		t.Errorf("request = %+v", req)
+	}
+	if _, ok := req.Payload["caption"]; ok {
+		t.Errorf("empty caption sent: %v", req.Payload)
+	}
+}
+
+func TestSendMediaTooLarge(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "huge.png")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := file.Truncate(MaxPhotoSize + 1); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+
+	calls := 0
+	transport := roundTripFunc(func(*http.Request) (*http.Response, error) {
+		calls++
+		return jsonResponse(http.StatusOK, map[string]any{"ok": true}), nil
+	})
+	svc := NewTelegramService(testConfig(), repository.NewMemoryStorage(), WithTransport(transport))
+
+	if _, err := svc.SendPhoto(context.Background(), "", path, ""); err == nil {
+		t.Error("oversized photo accepted")
+	}
+	if calls != 0 {
+		t.Errorf("made %d requests for an oversized photo", calls)
+	}
+}
@@ -0,0 +1,117 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/mdemidenko/monitoring-platform/internal/models"
+)
+
+// ErrQueued возвращается SendMessage, когда отправка приостановлена и уведомление поставлено в очередь
+var ErrQueued = errors.New("telegram sending is paused, notification queued")
+
+// Pause приостанавливает отправку: новые уведомления сохраняются и копятся в очереди
+func (s *TelegramService) Pause() {
+	s.pauseMu.Lock()
+	defer s.pauseMu.Unlock()
+
+	if !s.paused {
+		log.Println("⏸️  Отправка уведомлений приостановлена")
+	}
+	s.paused = true
+}
+
+// Resume возобновляет отправку и отправляет накопленные уведомления в порядке поступления
+func (s *TelegramService) Resume(ctx context.Context) ProcessResult {
+	s.pauseMu.Lock()
+	s.paused = false
+	queued := s.queue
+	s.queue = nil
+	s.pauseMu.Unlock()
+
+	log.Printf("▶️  Отправка уведомлений возобновлена, в очереди: %d", len(queued))
+
+	var result ProcessResult
+	for i, notification := range queued {
+		if ctx.Err() != nil || s.IsPaused() {
+			// Неотправленные уведомления возвращаем в начало очереди
+			s.requeue(queued[i:])
+			break
+		}
+
+		s.resend(ctx, notification, &result)
+	}
+
+	return result
+}
+
+// resend отправляет ранее отложенное уведомление и учитывает итог в result
+func (s *TelegramService) resend(ctx context.Context, notification *models.Notification, result *ProcessResult) {
+	sentNotifs, err := s.SendMessage(ctx, notification)
+	s.storeSent(sentNotifs)
+	s.deadLetter(notification, err)
+	if errors.Is(err, ErrNoResult) {
+		log.Printf("⚠️ Уведомление из очереди '%s': %v", notification.Text, err)
+		err = nil
+	}
+	s.stats.record(err)
+
+	switch {
+	case errors.Is(err, ErrDuplicate):
+		log.Printf("🔁 Повторное уведомление из очереди не отправлено: %s", notification.Text)
+		result.SuppressedCount++
+	case errors.Is(err, ErrQueued):
+		// Отправку снова приостановили, уведомление вернулось в очередь
+		result.QueuedCount++
+	case errors.Is(err, ErrDeferred):
+		// Начались тихие часы, уведомление снова отложено
+		result.DeferredCount++
+	case err != nil:
+		log.Printf("❌ Ошибка отправки уведомления из очереди '%s': %v", notification.Text, err)
+		result.ErrorCount++
+		if result.ErrorCategories == nil {
+			result.ErrorCategories = map[ErrorCategory]int{}
+		}
+		result.ErrorCategories[Categorize(err)]++
+	default:
+		result.SuccessCount++
+	}
+}
+
+// IsPaused сообщает, приостановлена ли отправка
+func (s *TelegramService) IsPaused() bool {
+	s.pauseMu.Lock()
+	defer s.pauseMu.Unlock()
+
+	return s.paused
+}
+
+// QueuedCount возвращает количество уведомлений, ожидающих возобновления отправки
+func (s *TelegramService) QueuedCount() int {
+	s.pauseMu.Lock()
+	defer s.pauseMu.Unlock()
+
+	return len(s.queue)
+}
+
+// enqueueIfPaused ставит уведомление в очередь, если отправка приостановлена
+func (s *TelegramService) enqueueIfPaused(notification *models.Notification) bool {
+	s.pauseMu.Lock()
+	defer s.pauseMu.Unlock()
+
+	if !s.paused {
+		return false
+	}
+	s.queue = append(s.queue, notification)
+	log.Printf("📥 Уведомление поставлено в очередь (пауза): %s", notification.Text)
+	return true
+}
+
+// requeue возвращает уведомления в начало очереди, сохраняя их порядок
+func (s *TelegramService) requeue(notifications []*models.Notification) {
+	s.pauseMu.Lock()
+	defer s.pauseMu.Unlock()
+
+	s.queue = append(append([]*models.Notification{}, notifications...), s.queue...)
+}
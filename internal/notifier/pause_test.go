@@ -0,0 +1,54 @@
+package notifier
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mdemidenko/monitoring-platform/internal/models"
+)
+
+func TestPauseQueuesNotifications(t *testing.T) {
+	fake := &fakeTelegram{}
+	svc, _ := newTestService(t, testConfig(), fake)
+
+	svc.Pause()
+	notifications := []*models.Notification{
+		models.NewNotification("", "first"),
+		models.NewNotification("", "second"),
+	}
+	held := svc.ProcessWithIntervals(context.Background(), notifications, 0, 1)
+
+	if held.SuccessCount != 0 || held.ErrorCount != 0 || held.QueuedCount != 2 {
+		t.Errorf("paused batch: success=%d errors=%d queued=%d, want 0, 0 and 2",
+			held.SuccessCount, held.ErrorCount, held.QueuedCount)
+	}
+	for _, result := range held.Results {
+		if result.Success {
+			t.Errorf("queued notification %d reported as sent", result.Index)
+		}
+	}
+	if calls := len(fake.calls()); calls != 0 {
+		t.Errorf("paused batch made %d Telegram calls", calls)
+	}
+	if queued := svc.QueuedCount(); queued != 2 {
+		t.Errorf("QueuedCount() = %d, want 2", queued)
+	}
+	if sent := svc.Stats().Sent; sent != 0 {
+		t.Errorf("Stats().Sent = %d before resume", sent)
+	}
+
+	released := svc.Resume(context.Background())
+	if released.SuccessCount != 2 {
+		t.Errorf("Resume SuccessCount = %d, want 2", released.SuccessCount)
+	}
+	calls := fake.calls()
+	if len(calls) != 2 || calls[0]["text"] != "first" || calls[1]["text"] != "second" {
+		t.Errorf("resumed calls = %v, want first and second in order", calls)
+	}
+	if sent := svc.Stats().Sent; sent != 2 {
+		t.Errorf("Stats().Sent = %d after resume, want 2", sent)
+	}
+	if queued := svc.QueuedCount(); queued != 0 {
+		t.Errorf("QueuedCount() = %d after resume", queued)
+	}
+}
@@ -0,0 +1,86 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// ErrMessageNotFound сообщение не отправлялось этим сервисом
+var ErrMessageNotFound = errors.New("message not found in storage")
+
+// pinPayload тело запросов pinChatMessage/unpinChatMessage
+type pinPayload struct {
+	ChatID    string `json:"chat_id"`
+	MessageID int64  `json:"message_id"`
+}
+
+// PinNotification закрепляет отправленное сервисом сообщение в чате.
+// Пустой chatID означает чат по умолчанию.
+func (s *TelegramService) PinNotification(ctx context.Context, chatID string, messageID int64) error {
+	return s.setPinned(ctx, "pinChatMessage", chatID, messageID)
+}
+
+// UnpinNotification открепляет ранее закрепленное сообщение
+func (s *TelegramService) UnpinNotification(ctx context.Context, chatID string, messageID int64) error {
+	return s.setPinned(ctx, "unpinChatMessage", chatID, messageID)
+}
+
+func (s *TelegramService) setPinned(ctx context.Context, method, chatID string, messageID int64) error {
+	cfg := s.Config()
+	chatID = resolveChatID(cfg, chatID, cfg.Telegram.ChatID)
+
+	if !s.hasSentMessage(chatID, messageID) {
+		return fmt.Errorf("message %d in chat %s: %w", messageID, chatID, ErrMessageNotFound)
+	}
+
+	_, err := s.callAPI(ctx, cfg.Telegram.BotToken, method, pinPayload{ChatID: chatID, MessageID: messageID})
+	return err
+}
+
+// hasSentMessage проверяет, что сообщение message_id отправлено в чат chatID.
+// message_id уникален только в пределах чата, поэтому чат тоже сравнивается:
+// числовой chatID — с чатом из ответа Telegram, а @username — с чатом
+// исходного уведомления.
+func (s *TelegramService) hasSentMessage(chatID string, messageID int64) bool {
+	numericID, numericErr := strconv.ParseInt(chatID, 10, 64)
+
+	for _, sent := range s.storage.GetSentNotifications() {
+		if sent.MessageID != messageID {
+			continue
+		}
+		if numericErr == nil && sent.ChatID != 0 {
+			if sent.ChatID == numericID {
+				return true
+			}
+			continue
+		}
+		if s.notificationChat(sent.NotificationID) == chatID {
+			return true
+		}
+	}
+	return false
+}
+
+// notificationChat возвращает чат, в который отправлялось уведомление id
+// (с учетом telegram.force_chat_id и чата по умолчанию), или пустую строку,
+// если уведомления нет
+func (s *TelegramService) notificationChat(id string) string {
+	if id == "" {
+		return ""
+	}
+	notification, ok := s.storage.GetNotificationByID(id)
+	if !ok {
+		return ""
+	}
+
+	if forced := s.Config().Telegram.ForceChatID; forced != "" {
+		return forced
+	}
+	if notification.ChatID != "" {
+		return notification.ChatID
+	}
+	_, defaultChatID, _ := s.resolveProfile(notification.Profile)
+	return defaultChatID
+}
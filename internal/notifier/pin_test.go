@@ -0,0 +1,89 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mdemidenko/monitoring-platform/internal/models"
+)
+
+func TestPinNotificationMatchesChat(t *testing.T) {
+	fake := &fakeTelegram{}
+	svc, _ := newTestService(t, testConfig(), fake)
+
+	// message_id 1 в чате 200, message_id 2 в канале @alerts
+	notifications := []*models.Notification{
+		models.NewNotification("200", "numeric chat"),
+		models.NewNotification("@alerts", "channel"),
+	}
+	if result := svc.ProcessWithIntervals(context.Background(), notifications, 0, 1); result.SuccessCount != 2 {
+		t.Fatalf("SuccessCount = %d, want 2", result.SuccessCount)
+	}
+
+	tests := []struct {
+		name      string
+		chatID    string
+		messageID int64
+		wantErr   error
+	}{
+		{name: "same numeric chat", chatID: "200", messageID: 1},
+		{name: "message id from another chat", chatID: "300", messageID: 1, wantErr: ErrMessageNotFound},
+		{name: "default chat", chatID: "", messageID: 1, wantErr: ErrMessageNotFound},
+		{name: "channel username", chatID: "@alerts", messageID: 2},
+		{name: "other channel", chatID: "@other", messageID: 2, wantErr: ErrMessageNotFound},
+		{name: "unknown message", chatID: "200", messageID: 42, wantErr: ErrMessageNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := svc.PinNotification(context.Background(), tt.chatID, tt.messageID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("PinNotification(%q, %d) = %v, want %v", tt.chatID, tt.messageID, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSentNotificationChatID(t *testing.T) {
+	fake := &fakeTelegram{}
+	svc, _ := newTestService(t, testConfig(), fake)
+
+	sent, err := svc.SendMessage(context.Background(), models.NewNotification("-100200", "alert"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Чат берется из result.chat.id ответа Telegram
+	if len(sent) != 1 || sent[0].ChatID != -100200 || sent[0].MessageID != 1 {
+		t.Errorf("sent = %+v, want message 1 in chat -100200", sent)
+	}
+}
+
+func TestPinAndUnpinRequests(t *testing.T) {
+	fake := &fakeTelegram{}
+	svc, _ := newTestService(t, testConfig(), fake)
+
+	notifications := []*models.Notification{models.NewNotification("200", "critical")}
+	if result := svc.ProcessWithIntervals(context.Background(), notifications, 0, 1); result.SuccessCount != 1 {
+		t.Fatalf("SuccessCount = %d, want 1", result.SuccessCount)
+	}
+
+	if err := svc.PinNotification(context.Background(), "200", 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.UnpinNotification(context.Background(), "200", 1); err != nil {
+		t.Fatal(err)
+	}
+
+	requests := fake.received()
+	if len(requests) != 3 {
+		t.Fatalf("made %d requests, want 3", len(requests))
+	}
+	for i, method := range []string{"pinChatMessage", "unpinChatMessage"} {
+		req := requests[i+1]
+		// Числа в JSON декодируются как float64
+		if req.Method != method || req.Payload["chat_id"] != "200" || req.Payload["message_id"] != float64(1) {
+			t.Errorf("request %d = %s %v, want %s for message 1 in chat 200", i+1, req.Method, req.Payload, method)
+		}
+	}
+}
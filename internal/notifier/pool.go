@@ -0,0 +1,118 @@
+package notifier
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// scaleCheckInterval период проверки глубины очереди при автомасштабировании
+const scaleCheckInterval = 100 * time.Millisecond
+
+// workerPool учитывает живые worker'ы и позволяет безопасно добавлять
+// и выводить их во время обработки пакета. Счетчик active меняется под
+// мьютексом раньше wg.Done, поэтому wg.Add вызывается только пока жив
+// хотя бы один worker и wg.Wait корректно завершается.
+type workerPool struct {
+	mu     sync.Mutex
+	wg     sync.WaitGroup
+	active int
+	peak   int
+	nextID int
+
+	minWorkers int
+	maxWorkers int
+}
+
+func newWorkerPool(minWorkers, maxWorkers int) *workerPool {
+	if maxWorkers < minWorkers {
+		maxWorkers = minWorkers
+	}
+	return &workerPool{minWorkers: minWorkers, maxWorkers: maxWorkers}
+}
+
+// start регистрирует стартовый worker и возвращает его номер
+func (p *workerPool) start() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.add()
+}
+
+// grow добавляет worker, если пул еще работает и не достиг максимума
+func (p *workerPool) grow() (int, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.active == 0 || p.active >= p.maxWorkers {
+		return 0, false
+	}
+	return p.add(), true
+}
+
+// retire выводит простаивающий worker, если их больше минимума
+func (p *workerPool) retire() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.active <= p.minWorkers {
+		return false
+	}
+	p.active--
+	return true
+}
+
+// exit вызывается при завершении worker'а; retired — worker уже выведен через retire
+func (p *workerPool) exit(retired bool) {
+	p.mu.Lock()
+	if !retired {
+		p.active--
+	}
+	p.mu.Unlock()
+
+	p.wg.Done()
+}
+
+// Peak возвращает максимальное число одновременно работавших worker'ов
+func (p *workerPool) Peak() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.peak
+}
+
+func (p *workerPool) add() int {
+	p.active++
+	p.nextID++
+	if p.active > p.peak {
+		p.peak = p.active
+	}
+	p.wg.Add(1)
+	return p.nextID
+}
+
+// autoscale добавляет worker'ов, пока очередь jobs не пустеет два замера подряд
+func (s *TelegramService) autoscale(ctx context.Context, pool *workerPool, jobs *jobQueue, results chan<- *workerResult, idleTimeout time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(scaleCheckInterval)
+	defer ticker.Stop()
+
+	backlogged := false
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-stop:
+			return
+		case <-ticker.C:
+			depth := jobs.Len()
+			if depth > 0 && backlogged {
+				if workerID, ok := pool.grow(); ok {
+					log.Printf("📈 Очередь %d, запускаем дополнительный worker %d", depth, workerID)
+					go s.notificationWorker(ctx, workerID, pool, jobs, results, idleTimeout)
+				}
+			}
+			backlogged = depth > 0
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package notifier
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/mdemidenko/monitoring-platform/internal/models"
+)
+
+func TestWorkerPoolBounds(t *testing.T) {
+	pool := newWorkerPool(2, 3)
+	pool.start()
+	pool.start()
+
+	if _, ok := pool.grow(); !ok {
+		t.Fatal("grow below the maximum failed")
+	}
+	if _, ok := pool.grow(); ok {
+		t.Fatal("grow above the maximum succeeded")
+	}
+	if !pool.retire() {
+		t.Fatal("retire above the minimum failed")
+	}
+	if pool.retire() {
+		t.Fatal("retire at the minimum succeeded")
+	}
+	if peak := pool.Peak(); peak != 3 {
+		t.Errorf("Peak() = %d, want 3", peak)
+	}
+}
+
+func TestProcessWithOptionsAutoscale(t *testing.T) {
+	tests := []struct {
+		name       string
+		workers    int
+		maxWorkers int
+		wantMin    int
+		wantMax    int
+	}{
+		{name: "fixed pool", workers: 1, maxWorkers: 0, wantMin: 1, wantMax: 1},
+		{name: "grows under backlog", workers: 1, maxWorkers: 4, wantMin: 2, wantMax: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeTelegram{delay: 30 * time.Millisecond}
+			svc, _ := newTestService(t, testConfig(), fake)
+
+			var notifications []*models.Notification
+			for i := range 30 {
+				notifications = append(notifications, models.NewNotification("", "alert "+strconv.Itoa(i)))
+			}
+
+			result := svc.ProcessWithOptions(context.Background(), notifications, ProcessOptions{
+				Workers:    tt.workers,
+				MaxWorkers: tt.maxWorkers,
+			})
+			if result.SuccessCount != len(notifications) {
+				t.Fatalf("SuccessCount = %d, want %d", result.SuccessCount, len(notifications))
+			}
+			if result.PeakWorkers < tt.wantMin || result.PeakWorkers > tt.wantMax {
+				t.Errorf("PeakWorkers = %d, want %d..%d", result.PeakWorkers, tt.wantMin, tt.wantMax)
+			}
+		})
+	}
+}
+
+func TestClampWorkers(t *testing.T) {
+	tests := []struct {
+		name           string
+		maxWorkers     int
+		opts           ProcessOptions
+		wantWorkers    int
+		wantMaxWorkers int
+	}{
+		{name: "zero workers", maxWorkers: 8, opts: ProcessOptions{Workers: 0}, wantWorkers: 1},
+		{name: "negative workers", maxWorkers: 8, opts: ProcessOptions{Workers: -3}, wantWorkers: 1},
+		{name: "within range", maxWorkers: 8, opts: ProcessOptions{Workers: 5}, wantWorkers: 5},
+		{name: "above max_workers", maxWorkers: 8, opts: ProcessOptions{Workers: 1000}, wantWorkers: 8},
+		{name: "default limit", opts: ProcessOptions{Workers: 1000}, wantWorkers: defaultMaxWorkers},
+		{name: "autoscale limit", maxWorkers: 8, opts: ProcessOptions{Workers: 2, MaxWorkers: 1000}, wantWorkers: 2, wantMaxWorkers: 8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			cfg.Telegram.MaxWorkers = tt.maxWorkers
+			svc, _ := newTestService(t, cfg, &fakeTelegram{})
+
+			got := svc.clampWorkers(tt.opts)
+			if got.Workers != tt.wantWorkers || got.MaxWorkers != tt.wantMaxWorkers {
+				t.Errorf("workers=%d max_workers=%d, want %d and %d", got.Workers, got.MaxWorkers, tt.wantWorkers, tt.wantMaxWorkers)
+			}
+		})
+	}
+}
+
+func TestProcessWithIntervalsClampedWorkers(t *testing.T) {
+	for _, workers := range []int{0, 1000} {
+		cfg := testConfig()
+		cfg.Telegram.MaxWorkers = 3
+		fake := &fakeTelegram{delay: 5 * time.Millisecond}
+		svc, _ := newTestService(t, cfg, fake)
+
+		var notifications []*models.Notification
+		for i := range 12 {
+			notifications = append(notifications, models.NewNotification("", "alert "+strconv.Itoa(i)))
+		}
+		result := svc.ProcessWithIntervals(context.Background(), notifications, 0, workers)
+		if result.SuccessCount != len(notifications) {
+			t.Errorf("workers=%d: SuccessCount = %d, want %d", workers, result.SuccessCount, len(notifications))
+		}
+		if result.PeakWorkers < 1 || result.PeakWorkers > 3 {
+			t.Errorf("workers=%d: PeakWorkers = %d, want 1..3", workers, result.PeakWorkers)
+		}
+	}
+}
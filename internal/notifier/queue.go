@@ -0,0 +1,76 @@
+package notifier
+
+import (
+	"container/heap"
+	"sync"
+)
+
+// jobQueue очередь уведомлений пакета с приоритетами. Все уведомления
+// помещаются в кучу заранее, а отправитель выдает worker'ам по одному
+// разрешению (токену в канале ready) на каждый интервал. Worker, получивший
+// токен, забирает уведомление с наибольшим Priority; при равном приоритете
+// сохраняется исходный порядок.
+type jobQueue struct {
+	mu    sync.Mutex
+	items jobHeap
+	ready chan struct{}
+}
+
+func newJobQueue(jobs []job) *jobQueue {
+	q := &jobQueue{
+		items: make(jobHeap, len(jobs)),
+		ready: make(chan struct{}, len(jobs)),
+	}
+	copy(q.items, jobs)
+	heap.Init(&q.items)
+	return q
+}
+
+// release разрешает worker'ам забрать еще одно уведомление
+func (q *jobQueue) release() {
+	q.ready <- struct{}{}
+}
+
+// close сообщает worker'ам, что новых разрешений не будет
+func (q *jobQueue) close() {
+	close(q.ready)
+}
+
+// pop возвращает уведомление с наибольшим приоритетом. Вызывается только
+// после получения токена из ready, поэтому куча не пуста.
+func (q *jobQueue) pop() job {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return heap.Pop(&q.items).(job)
+}
+
+// Len возвращает количество разрешенных, но еще не взятых уведомлений
+func (q *jobQueue) Len() int {
+	return len(q.ready)
+}
+
+// jobHeap реализует heap.Interface: сначала больший Priority, затем меньший Index
+type jobHeap []job
+
+func (h jobHeap) Len() int { return len(h) }
+
+func (h jobHeap) Less(i, j int) bool {
+	pi, pj := h[i].Notification.Priority, h[j].Notification.Priority
+	if pi != pj {
+		return pi > pj
+	}
+	return h[i].Index < h[j].Index
+}
+
+func (h jobHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *jobHeap) Push(x any) { *h = append(*h, x.(job)) }
+
+func (h *jobHeap) Pop() any {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	*h = old[:n-1]
+	return item
+}
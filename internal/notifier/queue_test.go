@@ -0,0 +1,64 @@
+package notifier
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/mdemidenko/monitoring-platform/internal/models"
+)
+
+func TestJobQueueOrder(t *testing.T) {
+	priorities := []int{0, 5, 1, 5, 0, 10, 1}
+	jobs := make([]job, len(priorities))
+	for i, priority := range priorities {
+		jobs[i] = job{Index: i, Notification: &models.Notification{Priority: priority}}
+	}
+
+	queue := newJobQueue(jobs)
+	var order []int
+	for range jobs {
+		queue.release()
+		order = append(order, queue.pop().Index)
+	}
+
+	// Сначала больший приоритет, при равном — исходный порядок
+	want := []int{5, 1, 3, 2, 6, 0, 4}
+	if !slices.Equal(order, want) {
+		t.Errorf("dequeue order = %v, want %v", order, want)
+	}
+}
+
+func TestProcessWithIntervalsPriority(t *testing.T) {
+	fake := &fakeTelegram{}
+	svc, _ := newTestService(t, testConfig(), fake)
+
+	notifications := []*models.Notification{
+		{Text: "routine 1"},
+		{Text: "critical", Priority: 10},
+		{Text: "routine 2"},
+		{Text: "important 1", Priority: 5},
+		{Text: "important 2", Priority: 5},
+	}
+	// Один worker забирает уведомления строго по очереди
+	result := svc.ProcessWithIntervals(context.Background(), notifications, 0, 1)
+	if result.SuccessCount != len(notifications) {
+		t.Fatalf("SuccessCount = %d, want %d", result.SuccessCount, len(notifications))
+	}
+
+	var sent []string
+	for _, call := range fake.calls() {
+		sent = append(sent, call["text"].(string))
+	}
+	want := []string{"critical", "important 1", "important 2", "routine 1", "routine 2"}
+	if !slices.Equal(sent, want) {
+		t.Errorf("send order = %v, want %v", sent, want)
+	}
+
+	// Итоги остаются в порядке входного списка
+	for i, res := range result.Results {
+		if res.Index != i {
+			t.Errorf("result %d has index %d", i, res.Index)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/mdemidenko/monitoring-platform/internal/models"
+)
+
+// ErrDeferred возвращается SendMessage, когда уведомление отложено до конца периода тишины
+var ErrDeferred = errors.New("quiet hours, notification deferred")
+
+// quietCheckInterval период проверки окончания тихих часов в RunQuietHours
+const quietCheckInterval = 30 * time.Second
+
+// deferIfQuiet откладывает некритичное уведомление, если сейчас тихие часы
+func (s *TelegramService) deferIfQuiet(notification *models.Notification) bool {
+	quiet := s.Config().Telegram.QuietHours
+	if quiet == nil || notification.Priority >= quiet.CriticalPriority {
+		return false
+	}
+
+	until, err := quiet.Until(s.now())
+	if err != nil || until.IsZero() {
+		return false
+	}
+
+	s.pauseMu.Lock()
+	s.deferred = append(s.deferred, notification)
+	s.pauseMu.Unlock()
+
+	log.Printf("🌙 Уведомление отложено до %s (тихие часы): %s", until.Format("15:04"), notification.Text)
+	return true
+}
+
+// DeferredCount возвращает количество уведомлений, отложенных до конца тихих часов
+func (s *TelegramService) DeferredCount() int {
+	s.pauseMu.Lock()
+	defer s.pauseMu.Unlock()
+
+	return len(s.deferred)
+}
+
+// ReleaseDeferred отправляет отложенные уведомления, если тихие часы закончились
+func (s *TelegramService) ReleaseDeferred(ctx context.Context) ProcessResult {
+	var result ProcessResult
+
+	if quiet := s.Config().Telegram.QuietHours; quiet != nil {
+		if until, err := quiet.Until(s.now()); err == nil && !until.IsZero() {
+			return result
+		}
+	}
+
+	s.pauseMu.Lock()
+	deferred := s.deferred
+	s.deferred = nil
+	s.pauseMu.Unlock()
+
+	if len(deferred) == 0 {
+		return result
+	}
+	log.Printf("☀️  Тихие часы закончились, отправляем отложенные: %d", len(deferred))
+
+	for i, notification := range deferred {
+		if ctx.Err() != nil {
+			// Неотправленные уведомления возвращаем в начало списка
+			s.pauseMu.Lock()
+			s.deferred = append(append([]*models.Notification{}, deferred[i:]...), s.deferred...)
+			s.pauseMu.Unlock()
+			break
+		}
+		s.resend(ctx, notification, &result)
+	}
+
+	return result
+}
+
+// RunQuietHours периодически отправляет отложенные уведомления после
+// окончания тихих часов, пока не отменен ctx
+func (s *TelegramService) RunQuietHours(ctx context.Context) {
+	ticker := time.NewTicker(quietCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if s.DeferredCount() > 0 {
+				s.ReleaseDeferred(ctx)
+			}
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mdemidenko/monitoring-platform/config"
+	"github.com/mdemidenko/monitoring-platform/internal/models"
+)
+
+func TestQuietHoursDefersByPriority(t *testing.T) {
+	night := time.Date(2026, 1, 1, 23, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		start    string
+		end      string
+		priority int
+		wantSent bool
+	}{
+		{name: "default priority inside the window", start: "22:00", end: "07:00", wantSent: false},
+		{name: "below critical inside the window", start: "22:00", end: "07:00", priority: config.DefaultCriticalPriority - 1, wantSent: false},
+		{name: "critical inside the window", start: "22:00", end: "07:00", priority: config.DefaultCriticalPriority, wantSent: true},
+		{name: "default priority outside the window", start: "01:00", end: "07:00", wantSent: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			cfg.Telegram.QuietHours = &config.QuietHoursConfig{
+				Start:            tt.start,
+				End:              tt.end,
+				Timezone:         "UTC",
+				CriticalPriority: config.DefaultCriticalPriority,
+			}
+			fake := &fakeTelegram{}
+			svc, _ := newTestService(t, cfg, fake)
+			svc.now = func() time.Time { return night }
+
+			_, err := svc.SendMessage(context.Background(), &models.Notification{Text: "alert", Priority: tt.priority})
+			if sent := len(fake.calls()) == 1; sent != tt.wantSent {
+				t.Fatalf("sent = %v, want %v", sent, tt.wantSent)
+			}
+			if tt.wantSent {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrDeferred) || svc.DeferredCount() != 1 {
+				t.Errorf("error = %v, deferred = %d; want ErrDeferred and 1", err, svc.DeferredCount())
+			}
+		})
+	}
+}
+
+func TestReleaseDeferredAfterQuietHours(t *testing.T) {
+	cfg := testConfig()
+	cfg.Telegram.QuietHours = &config.QuietHoursConfig{Start: "22:00", End: "07:00", Timezone: "UTC", CriticalPriority: config.DefaultCriticalPriority}
+	fake := &fakeTelegram{}
+	svc, _ := newTestService(t, cfg, fake)
+
+	now := time.Date(2026, 1, 1, 23, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	for _, text := range []string{"first", "second"} {
+		if _, err := svc.SendMessage(context.Background(), models.NewNotification("", text)); !errors.Is(err, ErrDeferred) {
+			t.Fatalf("error = %v, want ErrDeferred", err)
+		}
+	}
+
+	// До конца тихих часов ничего не отправляется
+	now = time.Date(2026, 1, 2, 6, 59, 0, 0, time.UTC)
+	if result := svc.ReleaseDeferred(context.Background()); result.SuccessCount != 0 || len(fake.calls()) != 0 {
+		t.Fatalf("released during quiet hours: %+v", result)
+	}
+
+	now = time.Date(2026, 1, 2, 7, 0, 0, 0, time.UTC)
+	result := svc.ReleaseDeferred(context.Background())
+	if result.SuccessCount != 2 || svc.DeferredCount() != 0 {
+		t.Errorf("released %d, %d left; want 2 and 0", result.SuccessCount, svc.DeferredCount())
+	}
+
+	calls := fake.calls()
+	if len(calls) != 2 || calls[0]["text"] != "first" || calls[1]["text"] != "second" {
+		t.Errorf("released out of order: %v", calls)
+	}
+}
@@ -0,0 +1,25 @@
+package notifier
+
+import (
+	"errors"
+	"net/url"
+	"regexp"
+)
+
+// botTokenPattern токен бота в пути URL Bot API (/bot<token>/method)
+var botTokenPattern = regexp.MustCompile(`/bot[^/]+/`)
+
+// redactURL маскирует токен бота в URL перед записью в лог
+func redactURL(rawURL string) string {
+	return botTokenPattern.ReplaceAllString(rawURL, "/bot***/")
+}
+
+// redactError маскирует токен в URL ошибки транспорта: текст *url.Error
+// содержит полный адрес запроса и попадает в логи и результаты пакета
+func redactError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		urlErr.URL = redactURL(urlErr.URL)
+	}
+	return err
+}
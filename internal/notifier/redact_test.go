@@ -0,0 +1,86 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mdemidenko/monitoring-platform/internal/models"
+	"github.com/mdemidenko/monitoring-platform/internal/repository"
+)
+
+func TestRedactURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{url: "https://api.telegram.org/bot123:ABC-def/sendMessage", want: "https://api.telegram.org/bot***/sendMessage"},
+		{url: "http://localhost:8081/prefix/bot123:ABC/getMe", want: "http://localhost:8081/prefix/bot***/getMe"},
+		{url: "https://example.com/photo.png", want: "https://example.com/photo.png"},
+	}
+
+	for _, tt := range tests {
+		if got := redactURL(tt.url); got != tt.want {
+			t.Errorf("redactURL(%q) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestTokenNotLeaked(t *testing.T) {
+	const token = "123456:SECRET-token"
+
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	cfg := testConfig()
+	cfg.Telegram.BotToken = token
+	cfg.Telegram.Debug = true
+
+	// Ошибки транспорта содержат полный URL запроса
+	failing := roundTripFunc(func(*http.Request) (*http.Response, error) {
+		return nil, errors.New("connection refused")
+	})
+	svc := NewTelegramService(cfg, repository.NewMemoryStorage(), WithTransport(failing))
+
+	photo := filepath.Join(t.TempDir(), "graph.png")
+	writeFile(t, photo, "png")
+
+	var errs []error
+	_, err := svc.SendMessage(context.Background(), models.NewNotification("", "alert"))
+	errs = append(errs, err)
+	_, err = svc.SendPhoto(context.Background(), "", photo, "graph")
+	errs = append(errs, err)
+	errs = append(errs, svc.HealthCheck(context.Background()))
+
+	result := svc.ProcessWithIntervals(context.Background(), []*models.Notification{models.NewNotification("", "batch")}, 0, 1)
+
+	for i, err := range errs {
+		if err == nil {
+			t.Fatalf("call %d succeeded, want a network error", i)
+		}
+		if strings.Contains(err.Error(), token) {
+			t.Errorf("error leaks the token: %v", err)
+		}
+		if !strings.Contains(err.Error(), "/bot***/") {
+			t.Errorf("error lost the redacted URL: %v", err)
+		}
+	}
+	if len(result.Results) != 1 || strings.Contains(result.Results[0].Error, token) {
+		t.Errorf("batch result leaks the token: %+v", result.Results)
+	}
+
+	// В отладочном режиме запросы логируются, но без токена
+	if !strings.Contains(logs.String(), "Calling POST") {
+		t.Fatalf("debug log has no requests:\n%s", logs.String())
+	}
+	if strings.Contains(logs.String(), token) || strings.Contains(logs.String(), "SECRET") {
+		t.Errorf("log leaks the token:\n%s", logs.String())
+	}
+}
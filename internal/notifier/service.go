@@ -4,11 +4,16 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	"math/rand/v2"
 	"net/http"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"text/template"
 	"time"
 
 	"github.com/mdemidenko/monitoring-platform/config"
@@ -17,75 +22,366 @@ import (
 )
 
 type TelegramService struct {
-	config  *config.Config
+	config  atomic.Pointer[config.Config]
 	client  *http.Client
 	storage repository.Storage
+	breaker *circuitBreaker
+
+	// Кэш метаданных чатов для GetChat
+	chats *chatCache
+
+	// Суммарная статистика по всем пакетам
+	stats lifetimeStats
+
+	// Недавно отправленные уведомления для подавления повторов
+	dedup *deduplicator
+
+	// Файл неотправленных уведомлений (telegram.dead_letter_file)
+	deadLetters deadLetterFile
+
+	// Шаблоны сообщений, загруженные LoadTemplates
+	templates atomic.Pointer[template.Template]
+
+	// Состояние паузы и очередь отложенных уведомлений
+	pauseMu sync.Mutex
+	paused  bool
+	queue   []*models.Notification
+	// deferred уведомления, отложенные до конца тихих часов
+	deferred []*models.Notification
+
+	// now источник текущего времени для тихих часов
+	now func() time.Time
+
+	// inflight учитывает выполняющиеся пакетные обработки для Shutdown
+	inflight sync.WaitGroup
 }
 
-type NotificationResponse struct {
-	OK     bool   `json:"ok"`
-	Error  string `json:"description,omitempty"`
-	Result *models.SentNotification `json:"result,omitempty"`
+// APIResponse ответ Telegram Bot API; Result зависит от вызванного метода
+type APIResponse struct {
+	OK         bool                `json:"ok"`
+	ErrorCode  int                 `json:"error_code,omitempty"`
+	Error      string              `json:"description,omitempty"`
+	Parameters *ResponseParameters `json:"parameters,omitempty"`
+	Result     json.RawMessage     `json:"result,omitempty"`
+}
+
+// ResponseParameters дополнительные сведения об ошибке от Telegram
+type ResponseParameters struct {
+	RetryAfter int `json:"retry_after,omitempty"`
+}
+
+// sendMessagePayload тело запроса sendMessage (только поля, которые ожидает Telegram)
+type sendMessagePayload struct {
+	ChatID      string                       `json:"chat_id"`
+	Text        string                       `json:"text"`
+	ParseMode   string                       `json:"parse_mode,omitempty"`
+	ThreadID    int                          `json:"message_thread_id,omitempty"`
+	ReplyTo     int64                        `json:"reply_to_message_id,omitempty"`
+	LinkPreview *linkPreviewOptions          `json:"link_preview_options,omitempty"`
+	ReplyMarkup *models.InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+}
+
+// linkPreviewOptions параметры предпросмотра ссылок (заменяют disable_web_page_preview)
+type linkPreviewOptions struct {
+	IsDisabled bool `json:"is_disabled,omitempty"`
 }
 
 // ProcessResult результат обработки всех уведомлений
 type ProcessResult struct {
 	SuccessCount int
 	ErrorCount   int
+	// SuppressedCount уведомления, не отправленные как повторы (не входят в ErrorCount)
+	SuppressedCount int
+	// QueuedCount уведомления, поставленные в очередь паузы (не входят в SuccessCount и ErrorCount)
+	QueuedCount int
+	// DeferredCount уведомления, отложенные до конца тихих часов (не входят в SuccessCount и ErrorCount)
+	DeferredCount int
+	// ErrorCategories число ошибок по категориям (сумма равна ErrorCount)
+	ErrorCategories map[ErrorCategory]int
+	// Results итоги по каждому уведомлению в порядке входного списка
+	Results []MessageResult
+	// PeakWorkers максимальное число одновременно работавших worker'ов
+	PeakWorkers int
+
+	// TotalDuration длительность обработки всего пакета
+	TotalDuration time.Duration
+	// Задержка обработки одного уведомления worker'ом (по обработанным уведомлениям)
+	AvgLatency time.Duration
+	MinLatency time.Duration
+	MaxLatency time.Duration
+}
+
+// ProcessOptions параметры пакетной обработки уведомлений
+type ProcessOptions struct {
+	// Interval пауза между постановкой уведомлений в очередь
+	Interval time.Duration
+	// Workers число worker'ов (минимум при автомасштабировании)
+	Workers int
+	// MaxWorkers включает автомасштабирование, если больше Workers
+	MaxWorkers int
+	// ScaleIdleTimeout простой, после которого лишний worker завершается
+	// (по умолчанию два интервала)
+	ScaleIdleTimeout time.Duration
+	// Jitter случайное отклонение интервала в пределах ±Jitter
+	Jitter time.Duration
+	// Rand источник случайности для Jitter (по умолчанию глобальный)
+	Rand *rand.Rand
+}
+
+// MessageResult итог обработки одного уведомления из пакета
+type MessageResult struct {
+	Index     int    `json:"index"`
+	ChatID    string `json:"chat_id"`
+	Success   bool   `json:"success"`
+	Error     string `json:"error,omitempty"`
+	MessageID int64  `json:"message_id,omitempty"`
+	// Category категория ошибки, если уведомление не отправлено
+	Category ErrorCategory `json:"category,omitempty"`
+}
+
+// errNotProcessed итог уведомления, до которого не дошла очередь (например, при отмене)
+const errNotProcessed = "not processed"
+
+// job уведомление с его позицией во входном списке
+type job struct {
+	Index        int
+	Notification *models.Notification
 }
 
 // workerResult результат обработки уведомления воркером
 type workerResult struct {
-	Text  string
-	Error error
+	Index     int
+	Text      string
+	MessageID int64
+	Error     error
+	Latency   time.Duration
 }
 
-func NewTelegramService(cfg *config.Config, storage repository.Storage) *TelegramService {
-	timeout := time.Duration(cfg.Telegram.Timeout) * time.Second
-	
+// storeBatchSize сколько SentNotification worker копит перед сохранением
+// в хранилище одной операцией
+const storeBatchSize = 32
+
+// Значения пула соединений по умолчанию, если они не заданы в конфигурации
+const (
+	defaultMaxIdleConns        = 100
+	defaultMaxIdleConnsPerHost = 10
+	defaultIdleConnTimeout     = 90 * time.Second
+
+	defaultBreakerThreshold   = 5
+	defaultBreakerCooldown    = 30 * time.Second
+	defaultBreakerMaxCooldown = 5 * time.Minute
+
+	defaultMaxWorkers = 10
+
+	defaultMaxResponseSize = 4 << 20
+
+	defaultChatCacheSize = 256
+	defaultChatCacheTTL  = 5 * time.Minute
+)
+
+// Option настраивает TelegramService при создании
+type Option func(*TelegramService)
+
+// WithTransport подменяет транспорт HTTP клиента (например, для тестов)
+func WithTransport(transport http.RoundTripper) Option {
+	return func(s *TelegramService) {
+		s.client.Transport = transport
+	}
+}
+
+func NewTelegramService(cfg *config.Config, storage repository.Storage, opts ...Option) *TelegramService {
+	timeout := cfg.Telegram.Timeout.Duration()
+
 	client := &http.Client{
-		Timeout: timeout,
+		Timeout:   timeout,
+		Transport: newTransport(cfg.Telegram),
 	}
 
-	return &TelegramService{
-		config:  cfg,
+	s := &TelegramService{
 		client:  client,
 		storage: storage,
+		breaker: newBreaker(cfg.Telegram),
+		chats:   newChatCacheFromConfig(cfg.Telegram),
+		dedup:   newDeduplicator(),
+		now:     time.Now,
+	}
+	s.config.Store(cfg)
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// newTransport создает HTTP транспорт с настроенным пулом соединений
+func newTransport(cfg config.TelegramConfig) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	transport.MaxIdleConns = defaultMaxIdleConns
+	if cfg.MaxIdleConns > 0 {
+		transport.MaxIdleConns = cfg.MaxIdleConns
+	}
+
+	transport.MaxIdleConnsPerHost = defaultMaxIdleConnsPerHost
+	if cfg.MaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = cfg.MaxIdleConnsPerHost
+	}
+
+	transport.IdleConnTimeout = defaultIdleConnTimeout
+	if cfg.IdleConnTimeout > 0 {
+		transport.IdleConnTimeout = time.Duration(cfg.IdleConnTimeout) * time.Second
 	}
+
+	return transport
+}
+
+// newBreaker создает circuit breaker по настройкам из конфигурации
+func newBreaker(cfg config.TelegramConfig) *circuitBreaker {
+	threshold := defaultBreakerThreshold
+	if cfg.BreakerThreshold > 0 {
+		threshold = cfg.BreakerThreshold
+	}
+
+	cooldown := defaultBreakerCooldown
+	if cfg.BreakerCooldown > 0 {
+		cooldown = time.Duration(cfg.BreakerCooldown) * time.Second
+	}
+
+	maxCooldown := defaultBreakerMaxCooldown
+	if cfg.BreakerMaxCooldown > 0 {
+		maxCooldown = time.Duration(cfg.BreakerMaxCooldown) * time.Second
+	}
+
+	return newCircuitBreaker(threshold, cooldown, maxCooldown)
+}
+
+// Config возвращает текущую конфигурацию сервиса
+func (s *TelegramService) Config() *config.Config {
+	return s.config.Load()
+}
+
+// UpdateConfig атомарно подменяет конфигурацию; уже выполняющиеся
+// отправки завершаются со старыми значениями
+func (s *TelegramService) UpdateConfig(cfg *config.Config) {
+	s.config.Store(cfg)
+}
+
+// BreakerState возвращает текущее состояние circuit breaker для Telegram
+func (s *TelegramService) BreakerState() BreakerState {
+	return s.breaker.State()
 }
 
 // ProcessWithIntervals обрабатывает уведомления с интервалами между отправками
 func (s *TelegramService) ProcessWithIntervals(ctx context.Context, notifications []*models.Notification, interval time.Duration, numWorkers int) ProcessResult {
-	jobs := make(chan *models.Notification, len(notifications))
+	return s.ProcessWithOptions(ctx, notifications, ProcessOptions{
+		Interval: interval,
+		Workers:  numWorkers,
+	})
+}
+
+// ProcessWithOptions обрабатывает уведомления с интервалами между отправками
+// и, если задан MaxWorkers, масштабирует число worker'ов по глубине очереди
+func (s *TelegramService) ProcessWithOptions(ctx context.Context, notifications []*models.Notification, opts ProcessOptions) ProcessResult {
+	s.inflight.Add(1)
+	defer s.inflight.Done()
+
+	opts = s.clampWorkers(opts)
+	s.stats.batches.Add(1)
+	startedAt := time.Now()
+
+	queued := make([]job, len(notifications))
+	for i, notification := range notifications {
+		queued[i] = job{Index: i, Notification: notification}
+	}
+	jobs := newJobQueue(queued)
 	results := make(chan *workerResult, len(notifications))
 	done := make(chan bool)
+	poolDone := make(chan struct{})
 
-	var wg sync.WaitGroup
+	pool := newWorkerPool(opts.Workers, opts.MaxWorkers)
+
+	idleTimeout := time.Duration(0)
+	if pool.maxWorkers > pool.minWorkers {
+		idleTimeout = opts.ScaleIdleTimeout
+		if idleTimeout <= 0 {
+			idleTimeout = 2 * opts.Interval
+		}
+	}
 
 	// Запускаем worker'ы
-	for i := 0; i < numWorkers; i++ {
-		wg.Add(1)
-		go s.notificationWorker(ctx, i+1, &wg, jobs, results)
+	for i := 0; i < opts.Workers; i++ {
+		go s.notificationWorker(ctx, pool.start(), pool, jobs, results, idleTimeout)
 	}
 
 	// Отправляем уведомления с интервалами
-	go s.sendNotificationsWithIntervals(ctx, notifications, jobs, interval)
+	go s.sendNotificationsWithIntervals(ctx, notifications, jobs, opts)
+
+	// Добавляем worker'ы при росте очереди
+	if pool.maxWorkers > pool.minWorkers {
+		go s.autoscale(ctx, pool, jobs, results, idleTimeout, poolDone)
+	}
 
 	// Ждем завершения worker'ов
 	go func() {
-		wg.Wait()
+		pool.wg.Wait()
+		close(poolDone)
 		close(results)
 		done <- true
 	}()
 
 	// Обрабатываем результаты
-	return s.processResults(ctx, results, done)
+	result := s.processResults(ctx, notifications, results, done)
+	result.PeakWorkers = pool.Peak()
+	result.TotalDuration = time.Since(startedAt)
+	return result
 }
 
-// sendNotificationsWithIntervals отправляет уведомления с интервалами
-func (s *TelegramService) sendNotificationsWithIntervals(ctx context.Context, notifications []*models.Notification, jobs chan<- *models.Notification, interval time.Duration) {
-	ticker := time.NewTicker(interval)
-	defer ticker.Stop()
+// clampWorkers приводит число worker'ов к диапазону [1, telegram.max_workers],
+// чтобы пакет обрабатывался при любых значениях от вызывающего кода
+func (s *TelegramService) clampWorkers(opts ProcessOptions) ProcessOptions {
+	limit := defaultMaxWorkers
+	if maxWorkers := s.Config().Telegram.MaxWorkers; maxWorkers > 0 {
+		limit = maxWorkers
+	}
+
+	workers := min(max(opts.Workers, 1), limit)
+	if workers != opts.Workers {
+		log.Printf("⚠️ Число worker'ов %d вне диапазона [1, %d], используется %d", opts.Workers, limit, workers)
+		opts.Workers = workers
+	}
+
+	if opts.MaxWorkers > limit {
+		log.Printf("⚠️ MaxWorkers %d больше допустимого %d, используется %d", opts.MaxWorkers, limit, limit)
+		opts.MaxWorkers = limit
+	}
+
+	return opts
+}
+
+// Shutdown ждет завершения выполняющихся пакетных обработок.
+// Сами обработки останавливаются отменой их контекста.
+func (s *TelegramService) Shutdown(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.inflight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("notifier shutdown: %w", ctx.Err())
+	}
+}
+
+// sendNotificationsWithIntervals выдает worker'ам уведомления из очереди с интервалами.
+// Какое уведомление будет отправлено, определяет приоритет в jobs.
+// Интервал пересчитывается перед каждым уведомлением, чтобы учесть jitter.
+func (s *TelegramService) sendNotificationsWithIntervals(ctx context.Context, notifications []*models.Notification, jobs *jobQueue, opts ProcessOptions) {
+	timer := time.NewTimer(nextDelay(opts))
+	defer timer.Stop()
 
 	sentCount := 0
 
@@ -93,63 +389,117 @@ func (s *TelegramService) sendNotificationsWithIntervals(ctx context.Context, no
 		select {
 		case <-ctx.Done():
 			log.Println("⏹️  Прерывание отправки уведомлений по сигналу")
-			close(jobs)
+			jobs.close()
 			return
-		case <-ticker.C:
+		case <-timer.C:
 			if sentCount >= len(notifications) {
-				close(jobs)
+				jobs.close()
 				log.Printf("✅ Все %d уведомлений поставлены в очередь", sentCount)
 				return
 			}
 
-			notification := notifications[sentCount]
-			log.Printf("📨 Постановка в очередь уведомления %d: %s", sentCount+1, notification.Text)
+			log.Printf("📨 Постановка в очередь уведомления %d из %d", sentCount+1, len(notifications))
+			jobs.release()
+			sentCount++
 
-			select {
-			case <-ctx.Done():
-				log.Println("⏹️  Прерывание отправки уведомлений по сигналу")
-				close(jobs)
-				return
-			case jobs <- notification:
-				sentCount++
-				if sentCount < len(notifications) {
-					log.Printf("⏰ Следующее уведомление через %v", interval)
-				}
+			delay := nextDelay(opts)
+			if sentCount < len(notifications) {
+				log.Printf("⏰ Следующее уведомление через %v", delay)
 			}
+			timer.Reset(delay)
 		}
 	}
 }
 
+// nextDelay возвращает интервал до следующего уведомления: Interval со
+// случайным отклонением в пределах ±Jitter, но не меньше нуля
+func nextDelay(opts ProcessOptions) time.Duration {
+	if opts.Jitter <= 0 {
+		return opts.Interval
+	}
+
+	span := int64(2*opts.Jitter) + 1
+	var offset int64
+	if opts.Rand != nil {
+		offset = opts.Rand.Int64N(span)
+	} else {
+		offset = rand.Int64N(span)
+	}
+
+	delay := opts.Interval - opts.Jitter + time.Duration(offset)
+	if delay < 0 {
+		return 0
+	}
+	return delay
+}
+
+// notificationWorker обрабатывает уведомления из очереди jobs
+// Если idleTimeout больше нуля, простаивающий worker сверх минимума пула завершается.
+func (s *TelegramService) notificationWorker(ctx context.Context, workerID int, pool *workerPool, jobs *jobQueue, results chan<- *workerResult, idleTimeout time.Duration) {
+	retired := false
+	defer func() { pool.exit(retired) }()
 
-// notificationWorker обрабатывает уведомления из канала jobs
-func (s *TelegramService) notificationWorker(ctx context.Context, workerID int, wg *sync.WaitGroup, jobs <-chan *models.Notification, results chan<- *workerResult) {
-	defer wg.Done()
+	// Отправленные сообщения сохраняются группами; остаток сохраняется при
+	// выходе worker'а (в том числе по отмене), до того как пул его отпустит
+	var pending []*models.SentNotification
+	defer func() { s.storeSent(pending) }()
 
 	log.Printf("Worker %d запущен", workerID)
 	defer log.Printf("👷 Worker %d завершил работу", workerID)
 
+	var idle <-chan time.Time
 	for {
+		if idleTimeout > 0 {
+			idle = time.After(idleTimeout)
+		}
+
 		select {
 		case <-ctx.Done():
 			log.Printf("Worker %d получил сигнал завершения", workerID)
 			return
-		case notification, ok := <-jobs:
+		case <-idle:
+			if pool.retire() {
+				retired = true
+				log.Printf("📉 Worker %d простаивает и выводится из пула", workerID)
+				return
+			}
+		case _, ok := <-jobs.ready:
 			if !ok {
 				return
 			}
+			j := jobs.pop()
+			notification := j.Notification
 
 			log.Printf("Worker %d обрабатывает: %s", workerID, notification.Text)
 
-			err := s.ProcessEntity(ctx, notification)
+			startedAt := time.Now()
+			sentNotifs, err := s.sendNotification(ctx, notification)
+			pending = append(pending, sentNotifs...)
+			if len(pending) >= storeBatchSize {
+				s.storeSent(pending)
+				pending = nil
+			}
+			if errors.Is(err, ErrNoResult) {
+				// Сообщение доставлено, но без объекта сообщения: это не ошибка отправки
+				log.Printf("⚠️ Worker %d: %v", workerID, err)
+				err = nil
+			}
+
+			result := &workerResult{
+				Index:   j.Index,
+				Text:    notification.Text,
+				Error:   err,
+				Latency: time.Since(startedAt),
+			}
+			if len(sentNotifs) > 0 {
+				result.MessageID = sentNotifs[0].MessageID
+			}
 
 			select {
 			case <-ctx.Done():
 				log.Printf("Worker %d прерван при отправке результата", workerID)
 				return
-			case results <- &workerResult{
-				Text:  notification.Text,
-				Error: err,
-			}:
+			case results <- result:
 				// Результат успешно отправлен
 			}
 		}
@@ -157,9 +507,24 @@ func (s *TelegramService) notificationWorker(ctx context.Context, workerID int,
 }
 
 // processResults обрабатывает результаты из канала results
-func (s *TelegramService) processResults(ctx context.Context, results <-chan *workerResult, done <-chan bool) ProcessResult {
+func (s *TelegramService) processResults(ctx context.Context, notifications []*models.Notification, results <-chan *workerResult, done <-chan bool) ProcessResult {
 	successCount := 0
 	errorCount := 0
+	suppressedCount := 0
+	queuedCount := 0
+	deferredCount := 0
+	categories := map[ErrorCategory]int{}
+
+	var latency latencyStats
+
+	messageResults := make([]MessageResult, len(notifications))
+	for i, notification := range notifications {
+		messageResults[i] = MessageResult{
+			Index:  i,
+			ChatID: notification.ChatID,
+			Error:  errNotProcessed,
+		}
+	}
 
 	for {
 		select {
@@ -171,120 +536,497 @@ func (s *TelegramService) processResults(ctx context.Context, results <-chan *wo
 			case <-time.After(2 * time.Second):
 				log.Println("⚠️  Таймаут ожидания завершения воркеров")
 			}
-			return ProcessResult{SuccessCount: successCount, ErrorCount: errorCount}
+			return latency.apply(ProcessResult{SuccessCount: successCount, ErrorCount: errorCount, SuppressedCount: suppressedCount, QueuedCount: queuedCount, DeferredCount: deferredCount, ErrorCategories: categories, Results: messageResults})
 		case result, ok := <-results:
 			if !ok {
 				<-done
-				return ProcessResult{SuccessCount: successCount, ErrorCount: errorCount}
+				return latency.apply(ProcessResult{SuccessCount: successCount, ErrorCount: errorCount, SuppressedCount: suppressedCount, QueuedCount: queuedCount, DeferredCount: deferredCount, ErrorCategories: categories, Results: messageResults})
 			}
-			if result.Error != nil {
+			latency.add(result.Latency)
+			s.stats.record(result.Error)
+			messageResult := &messageResults[result.Index]
+			if errors.Is(result.Error, ErrDuplicate) {
+				log.Printf("🔁 Повторное уведомление не отправлено: %s", result.Text)
+				messageResult.Error = result.Error.Error()
+				suppressedCount++
+			} else if errors.Is(result.Error, ErrQueued) {
+				log.Printf("📥 Уведомление ждет возобновления отправки: %s", result.Text)
+				messageResult.Error = result.Error.Error()
+				queuedCount++
+			} else if errors.Is(result.Error, ErrDeferred) {
+				log.Printf("🌙 Уведомление ждет окончания тихих часов: %s", result.Text)
+				messageResult.Error = result.Error.Error()
+				deferredCount++
+			} else if result.Error != nil {
 				log.Printf("❌ Ошибка обработки уведомления '%s': %v", result.Text, result.Error)
+				messageResult.Error = result.Error.Error()
+				messageResult.Category = Categorize(result.Error)
+				categories[messageResult.Category]++
 				errorCount++
 			} else {
 				log.Printf("✅ Уведомление успешно обработано: %s", result.Text)
+				messageResult.Success = true
+				messageResult.Error = ""
+				messageResult.MessageID = result.MessageID
 				successCount++
 			}
 		}
 	}
 }
 
+// latencyStats накапливает задержки обработки уведомлений
+type latencyStats struct {
+	count    int
+	total    time.Duration
+	min, max time.Duration
+}
+
+func (l *latencyStats) add(d time.Duration) {
+	if l.count == 0 || d < l.min {
+		l.min = d
+	}
+	if d > l.max {
+		l.max = d
+	}
+	l.total += d
+	l.count++
+}
+
+// apply заполняет поля задержек в результате
+func (l *latencyStats) apply(result ProcessResult) ProcessResult {
+	if l.count > 0 {
+		result.AvgLatency = l.total / time.Duration(l.count)
+		result.MinLatency = l.min
+		result.MaxLatency = l.max
+	}
+	return result
+}
+
 // ProcessEntity обрабатывает сущности и сохраняет их в репозиторий
 func (s *TelegramService) ProcessEntity(ctx context.Context, entity any) error {
+	if notification, ok := entity.(*models.Notification); ok {
+		_, err := s.processNotification(ctx, notification)
+		return err
+	}
+
 	// Проверяем контекст перед началом работы
 	if err := ctx.Err(); err != nil {
 		return fmt.Errorf("operation cancelled: %w", err)
 	}
-	
+
 	// Сохраняем входящую сущность (происходит проверка типа)
 	if err := s.storage.Store(entity); err != nil {
 		return fmt.Errorf("failed to store entity: %w", err)
 	}
-	
-	// Дополнительная логика в зависимости от типа
-	switch v := entity.(type) {
-	case *models.Notification:
-		// Отправляем уведомление и получаем ответ от Telegram
-		sentNotif, err := s.SendNotification(ctx, v.Text)
-		if err != nil {
-			return err
-		}
-		
-		// Сохраняем ответ от Telegram (SentNotification)
-		if sentNotif != nil {
-			if err := s.storage.Store(sentNotif); err != nil {
-				log.Printf("Failed to store sent notification: %v", err)
-			}
-		}
-	case *models.SentNotification:
+
+	if v, ok := entity.(*models.SentNotification); ok {
 		// Если это SentNotification - просто логируем
-		log.Printf("Sent notification stored: MessageID=%d, ChatID=%d", v.MessageID, v.ChatID)
+		log.Printf("Sent notification stored: MessageID=%d, ChatID=%d, SentAt=%s",
+			v.MessageID, v.ChatID, v.SentAt.Format(time.RFC3339))
 	}
-	
+
 	return nil
 }
 
-// SendNotification отправляет уведомление в Telegram
-func (s *TelegramService) SendNotification(ctx context.Context, text string) (*models.SentNotification, error) {
+// processNotification сохраняет уведомление, отправляет его в Telegram
+// и сохраняет полученные SentNotification
+func (s *TelegramService) processNotification(ctx context.Context, notification *models.Notification) ([]*models.SentNotification, error) {
+	sentNotifs, err := s.sendNotification(ctx, notification)
+	s.storeSent(sentNotifs)
+	return sentNotifs, err
+}
+
+// sendNotification сохраняет уведомление и отправляет его в Telegram.
+// Полученные SentNotification, включая уже отправленные части длинного
+// сообщения при ошибке, сохраняет вызывающий.
+func (s *TelegramService) sendNotification(ctx context.Context, notification *models.Notification) ([]*models.SentNotification, error) {
+	// Проверяем контекст перед началом работы
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("operation cancelled: %w", err)
+	}
+
+	if err := s.storage.Store(notification); err != nil {
+		return nil, fmt.Errorf("failed to store entity: %w", err)
+	}
+
+	// Во время паузы и тихих часов уведомление только сохраняется и ждет отправки
+	if s.enqueueIfPaused(notification) {
+		return nil, ErrQueued
+	}
+	if s.deferIfQuiet(notification) {
+		return nil, ErrDeferred
+	}
+
+	// Отправляем уведомление и получаем ответ от Telegram
+	sentNotifs, err := s.SendMessage(ctx, notification)
+	s.deadLetter(notification, err)
+
+	return sentNotifs, err
+}
+
+// storeSent сохраняет ответы Telegram (SentNotification) одной операцией
+func (s *TelegramService) storeSent(sentNotifs []*models.SentNotification) {
+	if len(sentNotifs) == 0 {
+		return
+	}
+
+	entities := make([]any, len(sentNotifs))
+	for i, sentNotif := range sentNotifs {
+		entities[i] = sentNotif
+	}
+	if err := s.storage.StoreBatch(entities); err != nil {
+		log.Printf("Failed to store sent notifications: %v", err)
+	}
+}
+
+// SendNotification отправляет текст в чат по умолчанию из конфигурации
+func (s *TelegramService) SendNotification(ctx context.Context, text string) ([]*models.SentNotification, error) {
+	return s.SendMessage(ctx, models.NewNotification(s.Config().Telegram.ChatID, text))
+}
+
+// SendMessage отправляет уведомление в Telegram. Текст длиннее лимита Telegram
+// разбивается на несколько сообщений (если это не отключено в уведомлении),
+// поэтому возвращаются все отправленные части по порядку.
+func (s *TelegramService) SendMessage(ctx context.Context, notification *models.Notification) (sent []*models.SentNotification, err error) {
 	// Проверяем контекст перед началом
 	if err := ctx.Err(); err != nil {
 		return nil, fmt.Errorf("operation cancelled: %w", err)
 	}
 
-	notification := models.NewNotification(s.config.Telegram.ChatID, text)
+	if notification.Template != "" {
+		text, err := s.RenderTemplate(notification.Template, notification.Data)
+		if err != nil {
+			return nil, err
+		}
+		// Уведомление может уже лежать в хранилище и читаться другими
+		// горутинами, поэтому текст подставляется в копию
+		rendered := *notification
+		rendered.Text = text
+		rendered.Template = ""
+		notification = &rendered
+	}
+
+	if notification.ReplyMarkup != nil {
+		if err := notification.ReplyMarkup.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid reply markup: %w", err)
+		}
+	}
+
+	if s.enqueueIfPaused(notification) {
+		return nil, ErrQueued
+	}
+	if s.deferIfQuiet(notification) {
+		return nil, ErrDeferred
+	}
+
+	token, defaultChatID, err := s.resolveProfile(notification.Profile)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := s.buildPayload(notification, defaultChatID)
+
+	if window := time.Duration(s.Config().Telegram.DedupWindow) * time.Second; window > 0 {
+		key := dedupKey{chatID: payload.ChatID, text: payload.Text}
+		if !s.dedup.reserve(key, window) {
+			return nil, ErrDuplicate
+		}
+		defer func() {
+			// Доставленное без объекта сообщения уведомление считается отправленным
+			if err != nil && !errors.Is(err, ErrNoResult) {
+				s.dedup.release(key)
+			}
+		}()
+	}
+
+	parts := []string{payload.Text}
+	if notification.SplitLong == nil || *notification.SplitLong {
+		parts = splitMessage(payload.Text, MaxMessageLength, payload.ParseMode)
+	}
+
+	sent = make([]*models.SentNotification, 0, len(parts))
+	missing := 0
+	for i, part := range parts {
+		partPayload := payload
+		partPayload.Text = part
+		// Клавиатура прикрепляется только к последней части, ответ — к первой
+		if i < len(parts)-1 {
+			partPayload.ReplyMarkup = nil
+		}
+		if i > 0 {
+			partPayload.ReplyTo = 0
+		}
+
+		sentNotif, err := s.sendPayload(ctx, token, partPayload)
+		if errors.Is(err, ErrNoResult) {
+			missing++
+			continue
+		}
+		if err != nil {
+			if len(parts) > 1 {
+				err = fmt.Errorf("failed to send part %d of %d: %w", i+1, len(parts), err)
+			}
+			return sent, err
+		}
+		sentNotif.NotificationID = notification.ID
+		sent = append(sent, sentNotif)
+	}
+
+	if missing > 0 {
+		if len(parts) > 1 {
+			return sent, fmt.Errorf("%d of %d parts: %w", missing, len(parts), ErrNoResult)
+		}
+		return sent, ErrNoResult
+	}
+
+	return sent, nil
+}
+
+// sendPayload выполняет один вызов sendMessage
+func (s *TelegramService) sendPayload(ctx context.Context, token string, payload sendMessagePayload) (*models.SentNotification, error) {
+	result, err := s.callAPI(ctx, token, "sendMessage", payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeSentMessage(result)
+}
+
+// sentMessage поля объекта Message из ответа Telegram, которые сохраняет сервис
+type sentMessage struct {
+	MessageID int64 `json:"message_id"`
+	Chat      struct {
+		ID int64 `json:"id"`
+	} `json:"chat"`
+}
+
+// decodeSentMessage разбирает объект Message из поля result ответа.
+// Telegram возвращает чат вложенным объектом chat, а не полем chat_id.
+func decodeSentMessage(result json.RawMessage) (*models.SentNotification, error) {
+	if len(result) == 0 || string(result) == "null" {
+		return nil, ErrNoResult
+	}
+
+	var message sentMessage
+	if err := json.Unmarshal(result, &message); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &models.SentNotification{
+		MessageID: message.MessageID,
+		ChatID:    message.Chat.ID,
+		SentAt:    time.Now(),
+	}, nil
+}
 
-	jsonData, err := json.Marshal(notification)
+// callAPI вызывает метод Bot API с JSON телом и возвращает поле result ответа
+func (s *TelegramService) callAPI(ctx context.Context, token, method string, payload any) (json.RawMessage, error) {
+	jsonData, err := json.Marshal(payload)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal notification: %w", err)
+		return nil, fmt.Errorf("failed to marshal %s request: %w", method, err)
 	}
 
-	if s.config.Telegram.Debug {
-		log.Printf("Sending notification: %s", string(jsonData))
+	methodURL := s.methodURL(token, method)
+	if s.Config().Telegram.Debug {
+		log.Printf("Calling POST %s: %s", redactURL(methodURL), string(jsonData))
 	}
 
-	url := fmt.Sprintf("https://api.telegram.org/bot%s/sendMessage", s.config.Telegram.BotToken)
-	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "POST", methodURL, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 
+	return s.do(ctx, req)
+}
+
+// do выполняет подготовленный запрос к Bot API через circuit breaker
+// и разбирает ответ, превращая ok=false в APIError
+func (s *TelegramService) do(ctx context.Context, req *http.Request) (json.RawMessage, error) {
+	if err := s.breaker.Allow(); err != nil {
+		return nil, err
+	}
+
 	resp, err := s.client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+		err = redactError(err)
+		if ctx.Err() != nil {
+			s.breaker.Cancel()
+			return nil, fmt.Errorf("failed to send request: %w", err)
+		}
+		s.breaker.Failure()
+		return nil, networkError("failed to send request", err)
 	}
+	defer resp.Body.Close()
+	s.recordStatus(resp.StatusCode)
 
-	body, err := io.ReadAll(resp.Body)
+	limit := int64(defaultMaxResponseSize)
+	if maxSize := s.Config().Telegram.MaxResponseSize; maxSize > 0 {
+		limit = int64(maxSize)
+	}
+	// Читаем на байт больше лимита, чтобы отличить ответ ровно в лимит от превышения
+	body, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return nil, networkError("failed to read response", err)
+	}
+	if int64(len(body)) > limit {
+		return nil, fmt.Errorf("%w: more than %d bytes", ErrResponseTooLarge, limit)
 	}
 
-	if s.config.Telegram.Debug {
+	if s.Config().Telegram.Debug {
 		log.Printf("Response: %s", string(body))
 	}
 
-	var telegramResp NotificationResponse
+	var telegramResp APIResponse
 	if err := json.Unmarshal(body, &telegramResp); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
 
 	if !telegramResp.OK {
-		return nil, fmt.Errorf("telegram API error: %s", telegramResp.Error)
+		retryAfter := 0
+		if telegramResp.Parameters != nil {
+			retryAfter = telegramResp.Parameters.RetryAfter
+		}
+		return nil, newAPIError(telegramResp.ErrorCode, telegramResp.Error, retryAfter)
 	}
 
 	return telegramResp.Result, nil
 }
 
-// HealthCheck проверяет доступность бота
-func (s *TelegramService) HealthCheck() error {
-	url := fmt.Sprintf("https://api.telegram.org/bot%s/getMe", s.config.Telegram.BotToken)
-	resp, err := s.client.Get(url)
+// resolveProfile возвращает токен бота и чат по умолчанию для профиля.
+// Пустое имя профиля означает основного бота из конфигурации.
+func (s *TelegramService) resolveProfile(name string) (token, chatID string, err error) {
+	cfg := s.Config()
+	if name == "" {
+		return cfg.Telegram.BotToken, cfg.Telegram.ChatID, nil
+	}
+
+	profile, ok := cfg.Telegram.Profiles[name]
+	if !ok {
+		return "", "", fmt.Errorf("unknown telegram profile: %s", name)
+	}
+
+	chatID = profile.ChatID
+	if chatID == "" {
+		chatID = cfg.Telegram.ChatID
+	}
+
+	return profile.BotToken, chatID, nil
+}
+
+// buildPayload формирует тело запроса sendMessage: подставляет чат по умолчанию,
+// parse_mode из конфигурации и подпись, если они не переопределены в уведомлении
+func (s *TelegramService) buildPayload(notification *models.Notification, defaultChatID string) sendMessagePayload {
+	cfg := s.Config()
+
+	chatID := resolveChatID(cfg, notification.ChatID, defaultChatID)
+
+	parseMode := notification.ParseMode
+	if parseMode == "" {
+		parseMode = cfg.Telegram.DefaultParseMode
+	}
+
+	text := notification.Text
+	if footer := cfg.Telegram.MessageFooter; footer != "" && !notification.SkipFooter {
+		text += "\n\n" + escapeForParseMode(footer, parseMode)
+	}
+
+	payload := sendMessagePayload{
+		ChatID:      chatID,
+		Text:        text,
+		ParseMode:   parseMode,
+		ThreadID:    notification.ThreadID,
+		ReplyTo:     notification.ReplyToMessageID,
+		ReplyMarkup: notification.ReplyMarkup,
+	}
+	if notification.DisableWebPagePreview {
+		payload.LinkPreview = &linkPreviewOptions{IsDisabled: true}
+	}
+
+	return payload
+}
+
+// resolveChatID возвращает чат, в который уходит запрос: chatID или, если он
+// пуст, defaultChatID. Заданный telegram.force_chat_id заменяет любой чат,
+// исходный при этом записывается в лог.
+func resolveChatID(cfg *config.Config, chatID, defaultChatID string) string {
+	if chatID == "" {
+		chatID = defaultChatID
+	}
+
+	if forced := cfg.Telegram.ForceChatID; forced != "" && forced != chatID {
+		log.Printf("↪️  Сообщение для чата %s перенаправлено в %s (telegram.force_chat_id)", chatID, forced)
+		return forced
+	}
+	return chatID
+}
+
+// HealthCheck проверяет доступность основного бота и всех профилей
+func (s *TelegramService) HealthCheck(ctx context.Context) error {
+	cfg := s.Config()
+	if err := s.checkBot(ctx, cfg.Telegram.BotToken); err != nil {
+		return err
+	}
+
+	var errs []error
+	for name, profile := range cfg.Telegram.Profiles {
+		if err := s.checkBot(ctx, profile.BotToken); err != nil {
+			errs = append(errs, fmt.Errorf("profile %s: %w", name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// checkBot вызывает getMe для указанного токена
+func (s *TelegramService) checkBot(ctx context.Context, token string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.methodURL(token, "getMe"), nil)
 	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if err := s.breaker.Allow(); err != nil {
 		return fmt.Errorf("health check failed: %w", err)
 	}
 
+	resp, err := s.client.Do(req)
+	if err != nil {
+		err = redactError(err)
+		if ctx.Err() != nil {
+			s.breaker.Cancel()
+			return fmt.Errorf("health check failed: %w", err)
+		}
+		s.breaker.Failure()
+		return fmt.Errorf("health check failed (circuit breaker: %s): %w: %w", s.breaker.State(), ErrNetwork, err)
+	}
+	defer resp.Body.Close()
+	s.recordStatus(resp.StatusCode)
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return fmt.Errorf("health check failed: %w", ErrInvalidToken)
+	}
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("health check failed with status: %d", resp.StatusCode)
+		return fmt.Errorf("health check failed with status: %d (circuit breaker: %s)", resp.StatusCode, s.breaker.State())
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// recordStatus передает в circuit breaker результат запроса по HTTP статусу.
+// Ошибки сервера и 429 считаются сбоем Telegram, остальные ответы — успехом.
+func (s *TelegramService) recordStatus(statusCode int) {
+	if statusCode >= http.StatusInternalServerError || statusCode == http.StatusTooManyRequests {
+		s.breaker.Failure()
+		return
+	}
+	s.breaker.Success()
+}
+
+// methodURL формирует URL метода Bot API с учетом настроенного базового адреса
+func (s *TelegramService) methodURL(token, method string) string {
+	baseURL := s.Config().Telegram.APIBaseURL
+	if baseURL == "" {
+		baseURL = config.DefaultTelegramAPIBaseURL
+	}
+	return fmt.Sprintf("%s/bot%s/%s", strings.TrimRight(baseURL, "/"), token, method)
+}
@@ -0,0 +1,773 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mdemidenko/monitoring-platform/config"
+	"github.com/mdemidenko/monitoring-platform/internal/models"
+	"github.com/mdemidenko/monitoring-platform/internal/repository"
+)
+
+// fakeRequest запрос к Bot API, полученный fakeTelegram
+type fakeRequest struct {
+	Token   string
+	Method  string
+	Payload map[string]any
+}
+
+// fakeTelegram отвечает на вызовы Bot API как Telegram и запоминает запросы
+type fakeTelegram struct {
+	mu       sync.Mutex
+	requests []fakeRequest
+	nextID   int64
+
+	// fail возвращает описание ошибки Bot API для запроса (пусто — успех)
+	fail func(payload map[string]any) string
+	// delay задержка ответа на каждый запрос
+	delay time.Duration
+}
+
+func (f *fakeTelegram) RoundTrip(req *http.Request) (*http.Response, error) {
+	var payload map[string]any
+	if req.Body != nil {
+		if err := json.NewDecoder(req.Body).Decode(&payload); err != nil && err != io.EOF {
+			return nil, err
+		}
+	}
+
+	if f.delay > 0 {
+		select {
+		case <-time.After(f.delay):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+
+	// Путь запроса: /bot<token>/<method>
+	method := path.Base(req.URL.Path)
+	token := strings.TrimPrefix(path.Dir(req.URL.Path), "/bot")
+
+	f.mu.Lock()
+	f.requests = append(f.requests, fakeRequest{Token: token, Method: method, Payload: payload})
+	f.nextID++
+	id := f.nextID
+	f.mu.Unlock()
+
+	if f.fail != nil {
+		if description := f.fail(payload); description != "" {
+			return jsonResponse(http.StatusBadRequest, map[string]any{"ok": false, "error_code": 400, "description": description}), nil
+		}
+	}
+
+	var result any = true
+	switch method {
+	case "sendMessage", "sendPhoto", "sendDocument":
+		result = map[string]any{"message_id": id, "chat": map[string]any{"id": fakeChatID(payload)}}
+	case "getChat":
+		result = map[string]any{"id": fakeChatID(payload), "type": "group", "title": "chat " + payload["chat_id"].(string)}
+	case "getMe":
+		result = map[string]any{"id": 1, "is_bot": true}
+	}
+
+	return jsonResponse(http.StatusOK, map[string]any{"ok": true, "result": result}), nil
+}
+
+// fakeChatID числовой идентификатор чата, который Telegram вернул бы для chat_id
+// запроса; для @username канала — постоянный отрицательный идентификатор
+func fakeChatID(payload map[string]any) int64 {
+	chatID, _ := payload["chat_id"].(string)
+	if id, err := strconv.ParseInt(chatID, 10, 64); err == nil {
+		return id
+	}
+	return -1000000000001
+}
+
+// calls возвращает тела запросов, полученных на данный момент
+func (f *fakeTelegram) calls() []map[string]any {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	payloads := make([]map[string]any, len(f.requests))
+	for i, req := range f.requests {
+		payloads[i] = req.Payload
+	}
+	return payloads
+}
+
+// received возвращает копию запросов, полученных на данный момент
+func (f *fakeTelegram) received() []fakeRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]fakeRequest(nil), f.requests...)
+}
+
+func jsonResponse(status int, body any) *http.Response {
+	data, _ := json.Marshal(body)
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(string(data))),
+	}
+}
+
+func testConfig() *config.Config {
+	cfg := config.DefaultConfig()
+	cfg.Telegram.BotToken = "test-token"
+	cfg.Telegram.ChatID = "100"
+	return cfg
+}
+
+func newTestService(t *testing.T, cfg *config.Config, fake *fakeTelegram) (*TelegramService, *repository.MemoryStorage) {
+	t.Helper()
+
+	storage := repository.NewMemoryStorage()
+	return NewTelegramService(cfg, storage, WithTransport(fake)), storage
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// sentPayload отправляет уведомление и возвращает тело запроса sendMessage
+func sentPayload(t *testing.T, cfg *config.Config, notification *models.Notification) map[string]any {
+	t.Helper()
+
+	fake := &fakeTelegram{}
+	svc, _ := newTestService(t, cfg, fake)
+	if _, err := svc.SendMessage(context.Background(), notification); err != nil {
+		t.Fatal(err)
+	}
+
+	calls := fake.calls()
+	if len(calls) != 1 {
+		t.Fatalf("made %d Telegram calls, want 1", len(calls))
+	}
+	return calls[0]
+}
+
+func TestPayloadExcludesNotificationMetadata(t *testing.T) {
+	notification := models.NewNotification("200", "alert")
+	if notification.ID == "" || notification.CreatedAt.IsZero() {
+		t.Fatalf("NewNotification did not set id and created_at: %+v", notification)
+	}
+
+	payload := sentPayload(t, testConfig(), notification)
+	for _, key := range []string{"id", "created_at"} {
+		if _, ok := payload[key]; ok {
+			t.Errorf("payload contains %q: %v", key, payload)
+		}
+	}
+	if payload["chat_id"] != "200" || payload["text"] != "alert" {
+		t.Errorf("payload = %v", payload)
+	}
+}
+
+func TestAPIBaseURL(t *testing.T) {
+	var paths []string
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"ok": true, "result": {"message_id": 7, "chat": {"id": 100}}}`))
+	}))
+	defer server.Close()
+
+	cfg := testConfig()
+	// Завершающий слэш не должен давать двойной слэш в пути
+	cfg.Telegram.APIBaseURL = server.URL + "/"
+	svc := NewTelegramService(cfg, repository.NewMemoryStorage())
+
+	sent, err := svc.SendMessage(context.Background(), models.NewNotification("", "alert"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sent) != 1 || sent[0].MessageID != 7 {
+		t.Errorf("sent = %+v", sent)
+	}
+	if err := svc.HealthCheck(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"POST /bottest-token/sendMessage", "GET /bottest-token/getMe"}
+	if strings.Join(paths, ",") != strings.Join(want, ",") {
+		t.Errorf("requests = %v, want %v", paths, want)
+	}
+}
+
+func TestPayloadFormatting(t *testing.T) {
+	tests := []struct {
+		name          string
+		parseMode     string
+		footer        string
+		notification  *models.Notification
+		wantText      string
+		wantParseMode any
+	}{
+		{
+			name:         "no defaults",
+			notification: &models.Notification{Text: "alert"},
+			wantText:     "alert",
+		},
+		{
+			name:          "default parse mode and footer",
+			parseMode:     ParseModeHTML,
+			footer:        "sent by <bot>",
+			notification:  &models.Notification{Text: "<b>alert</b>"},
+			wantText:      "<b>alert</b>\n\nsent by &lt;bot&gt;",
+			wantParseMode: ParseModeHTML,
+		},
+		{
+			name:          "footer escaped for overridden parse mode",
+			parseMode:     ParseModeHTML,
+			footer:        "v1.0 (prod)",
+			notification:  &models.Notification{Text: "*alert*", ParseMode: ParseModeMarkdownV2},
+			wantText:      "*alert*\n\nv1\\.0 \\(prod\\)",
+			wantParseMode: ParseModeMarkdownV2,
+		},
+		{
+			name:          "footer skipped",
+			parseMode:     ParseModeHTML,
+			footer:        "sent by bot",
+			notification:  &models.Notification{Text: "alert", SkipFooter: true},
+			wantText:      "alert",
+			wantParseMode: ParseModeHTML,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			cfg.Telegram.DefaultParseMode = tt.parseMode
+			cfg.Telegram.MessageFooter = tt.footer
+
+			payload := sentPayload(t, cfg, tt.notification)
+			if payload["text"] != tt.wantText {
+				t.Errorf("text = %q, want %q", payload["text"], tt.wantText)
+			}
+			if payload["parse_mode"] != tt.wantParseMode {
+				t.Errorf("parse_mode = %v, want %v", payload["parse_mode"], tt.wantParseMode)
+			}
+		})
+	}
+}
+
+func TestProfileRouting(t *testing.T) {
+	cfg := testConfig()
+	cfg.Telegram.Profiles = map[string]config.TelegramProfile{
+		"ops":    {BotToken: "ops-token", ChatID: "500"},
+		"nochat": {BotToken: "nochat-token"},
+	}
+
+	tests := []struct {
+		name         string
+		notification *models.Notification
+		wantToken    string
+		wantChat     string
+		wantErr      bool
+	}{
+		{name: "main bot", notification: &models.Notification{Text: "a"}, wantToken: "test-token", wantChat: "100"},
+		{name: "profile bot and chat", notification: &models.Notification{Text: "b", Profile: "ops"}, wantToken: "ops-token", wantChat: "500"},
+		{name: "explicit chat wins over profile chat", notification: &models.Notification{Text: "c", Profile: "ops", ChatID: "600"}, wantToken: "ops-token", wantChat: "600"},
+		{name: "profile without chat uses default chat", notification: &models.Notification{Text: "d", Profile: "nochat"}, wantToken: "nochat-token", wantChat: "100"},
+		{name: "unknown profile", notification: &models.Notification{Text: "e", Profile: "missing"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeTelegram{}
+			svc, _ := newTestService(t, cfg, fake)
+
+			_, err := svc.SendMessage(context.Background(), tt.notification)
+			if tt.wantErr {
+				if err == nil || len(fake.received()) != 0 {
+					t.Errorf("error = %v, calls = %d; want an error and no calls", err, len(fake.received()))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			req := fake.received()[0]
+			if req.Token != tt.wantToken || req.Payload["chat_id"] != tt.wantChat {
+				t.Errorf("sent with token %q to chat %v, want %q and %q", req.Token, req.Payload["chat_id"], tt.wantToken, tt.wantChat)
+			}
+		})
+	}
+}
+
+func TestHealthCheckCancelled(t *testing.T) {
+	fake := &fakeTelegram{delay: time.Minute}
+	svc, _ := newTestService(t, testConfig(), fake)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	start := time.Now()
+	err := svc.HealthCheck(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("error = %v, want context.Canceled", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("HealthCheck returned after %v", elapsed)
+	}
+	// Отмена проверки не считается сбоем Telegram
+	if state := svc.breaker.State(); state != BreakerClosed {
+		t.Errorf("breaker state = %v after a cancelled check", state)
+	}
+}
+
+func TestProcessWithIntervalsPerMessageResults(t *testing.T) {
+	fake := &fakeTelegram{fail: func(payload map[string]any) string {
+		if payload["chat_id"] == "404" {
+			return "Bad Request: chat not found"
+		}
+		return ""
+	}}
+	svc, _ := newTestService(t, testConfig(), fake)
+
+	chats := []string{"100", "404", "200", "404", "300", "400"}
+	notifications := make([]*models.Notification, len(chats))
+	for i, chat := range chats {
+		notifications[i] = models.NewNotification(chat, "alert "+strconv.Itoa(i))
+	}
+
+	result := svc.ProcessWithIntervals(context.Background(), notifications, 0, 3)
+	if result.SuccessCount != 4 || result.ErrorCount != 2 {
+		t.Errorf("success=%d errors=%d, want 4 and 2", result.SuccessCount, result.ErrorCount)
+	}
+	if len(result.Results) != len(chats) {
+		t.Fatalf("got %d results, want %d", len(result.Results), len(chats))
+	}
+
+	for i, res := range result.Results {
+		wantSuccess := chats[i] != "404"
+		if res.Index != i || res.ChatID != chats[i] || res.Success != wantSuccess {
+			t.Errorf("result %d = %+v, want chat %s success=%v", i, res, chats[i], wantSuccess)
+			continue
+		}
+		if wantSuccess && (res.MessageID == 0 || res.Error != "") {
+			t.Errorf("result %d: successful send without message id: %+v", i, res)
+		}
+		if !wantSuccess && (res.Error == "" || res.MessageID != 0 || res.Category != CategoryInvalidChat) {
+			t.Errorf("result %d: failure without error details: %+v", i, res)
+		}
+	}
+}
+
+func TestNextDelayJitter(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval time.Duration
+		jitter   time.Duration
+		min, max time.Duration
+	}{
+		{name: "no jitter", interval: time.Second, min: time.Second, max: time.Second},
+		{name: "jitter around the interval", interval: time.Second, jitter: 200 * time.Millisecond, min: 800 * time.Millisecond, max: 1200 * time.Millisecond},
+		{name: "jitter larger than the interval", interval: 100 * time.Millisecond, jitter: time.Second, min: 0, max: 1100 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := ProcessOptions{Interval: tt.interval, Jitter: tt.jitter, Rand: rand.New(rand.NewPCG(1, 2))}
+			replay := ProcessOptions{Interval: tt.interval, Jitter: tt.jitter, Rand: rand.New(rand.NewPCG(1, 2))}
+
+			seen := make(map[time.Duration]bool)
+			for range 1000 {
+				delay := nextDelay(opts)
+				if delay < tt.min || delay > tt.max {
+					t.Fatalf("delay %v outside [%v, %v]", delay, tt.min, tt.max)
+				}
+				// Одинаковое зерно дает одинаковую последовательность
+				if again := nextDelay(replay); again != delay {
+					t.Fatalf("same seed gave %v and %v", delay, again)
+				}
+				seen[delay] = true
+			}
+			if tt.jitter > 0 && len(seen) < 100 {
+				t.Errorf("only %d distinct delays with jitter %v", len(seen), tt.jitter)
+			}
+		})
+	}
+}
+
+func TestProcessWithIntervalsLatency(t *testing.T) {
+	const delay = 5 * time.Millisecond
+	fake := &fakeTelegram{}
+	slow := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		// Задержка растет с номером запроса, чтобы min и max различались
+		fake.mu.Lock()
+		n := len(fake.requests)
+		fake.mu.Unlock()
+		time.Sleep(delay * time.Duration(1+n%3))
+		return fake.RoundTrip(req)
+	})
+	svc := NewTelegramService(testConfig(), repository.NewMemoryStorage(), WithTransport(slow))
+
+	var notifications []*models.Notification
+	for i := range 9 {
+		notifications = append(notifications, models.NewNotification("", "alert "+strconv.Itoa(i)))
+	}
+	result := svc.ProcessWithIntervals(context.Background(), notifications, 0, 3)
+	if result.SuccessCount != len(notifications) {
+		t.Fatalf("SuccessCount = %d, want %d", result.SuccessCount, len(notifications))
+	}
+
+	if result.MinLatency < delay {
+		t.Errorf("MinLatency = %v, want at least the backend delay %v", result.MinLatency, delay)
+	}
+	if !(result.MinLatency <= result.AvgLatency && result.AvgLatency <= result.MaxLatency) {
+		t.Errorf("latencies not ordered: min=%v avg=%v max=%v", result.MinLatency, result.AvgLatency, result.MaxLatency)
+	}
+	if result.MaxLatency <= result.MinLatency {
+		t.Errorf("MaxLatency = %v, want more than MinLatency %v", result.MaxLatency, result.MinLatency)
+	}
+	if result.TotalDuration < result.MaxLatency {
+		t.Errorf("TotalDuration = %v, shorter than MaxLatency %v", result.TotalDuration, result.MaxLatency)
+	}
+}
+
+func TestProcessWithIntervalsEmptyLatency(t *testing.T) {
+	svc, _ := newTestService(t, testConfig(), &fakeTelegram{})
+
+	result := svc.ProcessWithIntervals(context.Background(), nil, 0, 2)
+	if result.AvgLatency != 0 || result.MinLatency != 0 || result.MaxLatency != 0 {
+		t.Errorf("latencies for an empty batch: %+v", result)
+	}
+}
+
+func TestPayloadReplyMarkup(t *testing.T) {
+	keyboard := &models.InlineKeyboardMarkup{InlineKeyboard: [][]models.InlineKeyboardButton{{
+		{Text: "Acknowledge", CallbackData: "ack:42"},
+		{Text: "Runbook", URL: "https://wiki.example.com/runbook"},
+	}}}
+
+	payload := sentPayload(t, testConfig(), &models.Notification{Text: "alert", ReplyMarkup: keyboard})
+	markup, ok := payload["reply_markup"].(map[string]any)
+	if !ok {
+		t.Fatalf("reply_markup missing: %v", payload)
+	}
+	rows := markup["inline_keyboard"].([]any)
+	buttons := rows[0].([]any)
+	if len(rows) != 1 || len(buttons) != 2 {
+		t.Fatalf("inline_keyboard = %v", rows)
+	}
+	ack, runbook := buttons[0].(map[string]any), buttons[1].(map[string]any)
+	if ack["text"] != "Acknowledge" || ack["callback_data"] != "ack:42" || ack["url"] != nil {
+		t.Errorf("callback button = %v", ack)
+	}
+	if runbook["url"] != "https://wiki.example.com/runbook" || runbook["callback_data"] != nil {
+		t.Errorf("url button = %v", runbook)
+	}
+
+	payload = sentPayload(t, testConfig(), &models.Notification{Text: "alert"})
+	if _, ok := payload["reply_markup"]; ok {
+		t.Errorf("reply_markup sent without a keyboard: %v", payload)
+	}
+}
+
+func TestSendMessageInvalidReplyMarkup(t *testing.T) {
+	fake := &fakeTelegram{}
+	svc, _ := newTestService(t, testConfig(), fake)
+
+	keyboard := &models.InlineKeyboardMarkup{InlineKeyboard: [][]models.InlineKeyboardButton{{
+		{Text: "Open", URL: "javascript:alert(1)"},
+	}}}
+	if _, err := svc.SendMessage(context.Background(), &models.Notification{Text: "alert", ReplyMarkup: keyboard}); err == nil {
+		t.Error("invalid keyboard accepted")
+	}
+	if len(fake.calls()) != 0 {
+		t.Errorf("made %d Telegram calls for an invalid keyboard", len(fake.calls()))
+	}
+}
+
+// checkOptionalField проверяет, что key есть в теле запроса со значением want
+// или отсутствует, если want равно nil
+func checkOptionalField(t *testing.T, payload map[string]any, key string, want any) {
+	t.Helper()
+
+	got, ok := payload[key]
+	switch {
+	case want == nil && ok:
+		t.Errorf("%s = %v, want it omitted", key, got)
+	case want != nil && !ok:
+		t.Errorf("%s missing, want %v", key, want)
+	case want != nil && !reflect.DeepEqual(got, want):
+		t.Errorf("%s = %v, want %v", key, got, want)
+	}
+}
+
+func TestPayloadThreadID(t *testing.T) {
+	payload := sentPayload(t, testConfig(), &models.Notification{Text: "alert", ThreadID: 42})
+	// Числа в JSON декодируются как float64
+	checkOptionalField(t, payload, "message_thread_id", float64(42))
+	checkOptionalField(t, payload, "thread_id", nil)
+
+	payload = sentPayload(t, testConfig(), &models.Notification{Text: "alert"})
+	checkOptionalField(t, payload, "message_thread_id", nil)
+}
+
+func TestPayloadLinkPreview(t *testing.T) {
+	payload := sentPayload(t, testConfig(), &models.Notification{Text: "https://example.com", DisableWebPagePreview: true})
+	checkOptionalField(t, payload, "link_preview_options", map[string]any{"is_disabled": true})
+	// Устаревшее поле Bot API не отправляется вместе с link_preview_options
+	checkOptionalField(t, payload, "disable_web_page_preview", nil)
+
+	payload = sentPayload(t, testConfig(), &models.Notification{Text: "https://example.com"})
+	checkOptionalField(t, payload, "link_preview_options", nil)
+}
+
+func TestSendMessageWithoutResult(t *testing.T) {
+	for _, body := range []map[string]any{{"ok": true}, {"ok": true, "result": nil}} {
+		transport := roundTripFunc(func(*http.Request) (*http.Response, error) {
+			return jsonResponse(http.StatusOK, body), nil
+		})
+		svc := NewTelegramService(testConfig(), repository.NewMemoryStorage(), WithTransport(transport))
+
+		sent, err := svc.SendMessage(context.Background(), models.NewNotification("", "alert"))
+		if !errors.Is(err, ErrNoResult) || len(sent) != 0 {
+			t.Errorf("%v: sent=%v error=%v, want ErrNoResult", body, sent, err)
+		}
+
+		// В пакете такое уведомление считается доставленным
+		result := svc.ProcessWithIntervals(context.Background(), []*models.Notification{models.NewNotification("", "alert 2")}, 0, 1)
+		if result.SuccessCount != 1 || result.ErrorCount != 0 || !result.Results[0].Success {
+			t.Errorf("%v: batch result = %+v, want one success", body, result)
+		}
+	}
+}
+
+// TestConcurrentBatchesStats запускается с -race: два пакета одновременно
+// пишут в одно хранилище и общие счетчики сервиса
+func TestConcurrentBatchesStats(t *testing.T) {
+	fake := &fakeTelegram{fail: func(payload map[string]any) string {
+		if strings.HasPrefix(payload["text"].(string), "bad") {
+			return "Bad Request: chat not found"
+		}
+		return ""
+	}}
+	svc, storage := newTestService(t, testConfig(), fake)
+
+	batch := func(prefix string, good, bad int) []*models.Notification {
+		var notifications []*models.Notification
+		for i := range good {
+			notifications = append(notifications, models.NewNotification("", prefix+" "+strconv.Itoa(i)))
+		}
+		for i := range bad {
+			notifications = append(notifications, models.NewNotification("", "bad "+prefix+" "+strconv.Itoa(i)))
+		}
+		return notifications
+	}
+	batches := [][]*models.Notification{batch("first", 25, 5), batch("second", 30, 3)}
+
+	results := make([]ProcessResult, len(batches))
+	var wg sync.WaitGroup
+	for i, notifications := range batches {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = svc.ProcessWithIntervals(context.Background(), notifications, 0, 4)
+		}()
+	}
+	wg.Wait()
+
+	var sent, failed int64
+	for _, result := range results {
+		sent += int64(result.SuccessCount)
+		failed += int64(result.ErrorCount)
+	}
+	if sent != 55 || failed != 8 {
+		t.Errorf("batches sent=%d failed=%d, want 55 and 8", sent, failed)
+	}
+
+	stats := svc.Stats()
+	if stats.Batches != 2 || stats.Sent != sent || stats.Failed != failed {
+		t.Errorf("Stats() = %+v, want 2 batches, %d sent, %d failed", stats, sent, failed)
+	}
+	if stored := len(storage.GetSentNotifications()); int64(stored) != sent {
+		t.Errorf("stored %d sent notifications, want %d", stored, sent)
+	}
+}
+
+func TestPayloadReplyTo(t *testing.T) {
+	payload := sentPayload(t, testConfig(), &models.Notification{Text: "alert", ReplyToMessageID: 77})
+	checkOptionalField(t, payload, "reply_to_message_id", float64(77))
+
+	payload = sentPayload(t, testConfig(), &models.Notification{Text: "alert"})
+	checkOptionalField(t, payload, "reply_to_message_id", nil)
+
+	// Ответом отправляется только первая часть длинного сообщения
+	fake := &fakeTelegram{}
+	svc, _ := newTestService(t, testConfig(), fake)
+	text := strings.Repeat("x", MaxMessageLength+10)
+	if _, err := svc.SendMessage(context.Background(), &models.Notification{Text: text, ReplyToMessageID: 77}); err != nil {
+		t.Fatal(err)
+	}
+	calls := fake.calls()
+	if len(calls) != 2 {
+		t.Fatalf("made %d calls, want 2", len(calls))
+	}
+	checkOptionalField(t, calls[0], "reply_to_message_id", float64(77))
+	checkOptionalField(t, calls[1], "reply_to_message_id", nil)
+}
+
+func TestSendMessageResponseSizeLimit(t *testing.T) {
+	const limit = 256
+	response := `{"ok": true, "result": {"message_id": 7, "chat": {"id": 100}}}`
+
+	tests := []struct {
+		name    string
+		size    int
+		wantErr bool
+	}{
+		{name: "exactly the limit", size: limit},
+		{name: "one byte over the limit", size: limit + 1, wantErr: true},
+		{name: "far over the limit", size: 10 * limit, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				// Пробелы после JSON не мешают разбору ответа в пределах лимита
+				w.Write([]byte(response + strings.Repeat(" ", tt.size-len(response))))
+			}))
+			defer server.Close()
+
+			cfg := testConfig()
+			cfg.Telegram.APIBaseURL = server.URL
+			cfg.Telegram.MaxResponseSize = limit
+			svc := NewTelegramService(cfg, repository.NewMemoryStorage())
+
+			sent, err := svc.SendMessage(context.Background(), models.NewNotification("", "alert"))
+			if tt.wantErr {
+				if !errors.Is(err, ErrResponseTooLarge) {
+					t.Errorf("error = %v, want ErrResponseTooLarge", err)
+				}
+				return
+			}
+			if err != nil || len(sent) != 1 || sent[0].MessageID != 7 {
+				t.Errorf("sent = %+v, error = %v", sent, err)
+			}
+		})
+	}
+}
+
+func TestProcessWithIntervalsStoresAllSent(t *testing.T) {
+	fake := &fakeTelegram{}
+	svc, storage := newTestService(t, testConfig(), fake)
+
+	var notifications []*models.Notification
+	for i := range 2*storeBatchSize + 5 {
+		notifications = append(notifications, models.NewNotification("", "alert "+strconv.Itoa(i)))
+	}
+
+	result := svc.ProcessWithIntervals(context.Background(), notifications, 0, 4)
+	if result.SuccessCount != len(notifications) {
+		t.Fatalf("SuccessCount = %d, want %d", result.SuccessCount, len(notifications))
+	}
+	if got := len(storage.GetSentNotifications()); got != len(notifications) {
+		t.Errorf("stored %d sent notifications, want %d", got, len(notifications))
+	}
+}
+
+func TestProcessWithIntervalsStoresDeliveredOnCancel(t *testing.T) {
+	fake := &fakeTelegram{delay: 20 * time.Millisecond}
+	svc, storage := newTestService(t, testConfig(), fake)
+
+	var notifications []*models.Notification
+	for i := range 50 {
+		notifications = append(notifications, models.NewNotification("", "alert "+strconv.Itoa(i)))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 150*time.Millisecond)
+	defer cancel()
+	svc.ProcessWithIntervals(ctx, notifications, 0, 4)
+	if err := svc.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	delivered := len(fake.calls())
+	if delivered == 0 || delivered == len(notifications) {
+		t.Fatalf("delivered %d of %d, want a partial batch", delivered, len(notifications))
+	}
+	if got := len(storage.GetSentNotifications()); got != delivered {
+		t.Errorf("stored %d sent notifications, delivered %d", got, delivered)
+	}
+}
+
+func TestForceChatID(t *testing.T) {
+	tests := []struct {
+		name      string
+		forceChat string
+		chatIDs   []string
+		want      []string
+	}{
+		{
+			name:    "requested chats without override",
+			chatIDs: []string{"200", "", "300"},
+			want:    []string{"200", "100", "300"},
+		},
+		{
+			name:      "all chats forced",
+			forceChat: "999",
+			chatIDs:   []string{"200", "", "300"},
+			want:      []string{"999", "999", "999"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			cfg.Telegram.ForceChatID = tt.forceChat
+			fake := &fakeTelegram{}
+			svc, _ := newTestService(t, cfg, fake)
+
+			var notifications []*models.Notification
+			for _, chatID := range tt.chatIDs {
+				notifications = append(notifications, models.NewNotification(chatID, "alert "+chatID))
+			}
+			result := svc.ProcessWithIntervals(context.Background(), notifications, 0, 1)
+			if result.SuccessCount != len(tt.want) {
+				t.Fatalf("SuccessCount = %d, want %d", result.SuccessCount, len(tt.want))
+			}
+
+			var got []string
+			for _, payload := range fake.calls() {
+				got = append(got, payload["chat_id"].(string))
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("chat ids = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// Одиночная отправка тоже уходит в принудительный чат
+	cfg := testConfig()
+	cfg.Telegram.ForceChatID = "999"
+	if payload := sentPayload(t, cfg, models.NewNotification("200", "alert")); payload["chat_id"] != "999" {
+		t.Errorf("SendMessage chat_id = %v, want 999", payload["chat_id"])
+	}
+}
@@ -0,0 +1,183 @@
+package notifier
+
+import "strings"
+
+// MaxMessageLength максимальная длина текста одного сообщения Telegram
+const MaxMessageLength = 4096
+
+// splitMessage разбивает текст на части не длиннее limit символов.
+// Разрез выполняется по границе строки, иначе по границе слова, иначе
+// жестко по лимиту. Для текста с разметкой разрез допускается только там,
+// где все сущности (жирный, код, ссылки, HTML теги) закрыты.
+func splitMessage(text string, limit int, parseMode string) []string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return []string{text}
+	}
+
+	var parts []string
+	for len(runes) > limit {
+		cut := findCut(runes, limit, parseMode)
+
+		part := strings.TrimRight(string(runes[:cut]), " \n")
+		if part != "" {
+			parts = append(parts, part)
+		}
+		runes = []rune(strings.TrimLeft(string(runes[cut:]), " \n"))
+	}
+	if len(runes) > 0 {
+		parts = append(parts, string(runes))
+	}
+
+	return parts
+}
+
+// findCut возвращает позицию разреза в пределах limit
+func findCut(runes []rune, limit int, parseMode string) int {
+	safe := safeCutPositions(runes, limit, parseMode)
+
+	lastLine, lastWord, lastSafe := 0, 0, 0
+	for i := 1; i <= limit; i++ {
+		if !safe[i] {
+			continue
+		}
+		lastSafe = i
+		switch runes[i-1] {
+		case '\n':
+			lastLine = i
+		case ' ':
+			lastWord = i
+		}
+	}
+
+	switch {
+	case lastLine > 0:
+		return lastLine
+	case lastWord > 0:
+		return lastWord
+	case lastSafe > 0:
+		return lastSafe
+	default:
+		return limit
+	}
+}
+
+// safeCutPositions отмечает позиции i (разрез перед runes[i]), в которых
+// не остается открытых сущностей разметки
+func safeCutPositions(runes []rune, limit int, parseMode string) []bool {
+	safe := make([]bool, limit+1)
+
+	switch parseMode {
+	case ParseModeMarkdown, ParseModeMarkdownV2:
+		markMarkdownSafe(runes, limit, parseMode == ParseModeMarkdownV2, safe)
+	case ParseModeHTML:
+		markHTMLSafe(runes, limit, safe)
+	default:
+		for i := range safe {
+			safe[i] = true
+		}
+	}
+
+	return safe
+}
+
+// markMarkdownSafe отслеживает открытые маркеры Markdown/MarkdownV2
+func markMarkdownSafe(runes []rune, limit int, v2 bool, safe []bool) {
+	open := map[string]bool{}
+	inPre, inCode, inLinkText, inLinkURL := false, false, false, false
+
+	balanced := func() bool {
+		for _, isOpen := range open {
+			if isOpen {
+				return false
+			}
+		}
+		return !inPre && !inCode && !inLinkText && !inLinkURL
+	}
+
+	safe[0] = true
+	for i := 0; i < limit && i < len(runes); {
+		step := 1
+		r := runes[i]
+
+		switch {
+		case v2 && r == '\\' && !inPre && !inCode:
+			step = 2
+		case hasPrefix(runes, i, "```"):
+			inPre = !inPre
+			step = 3
+		case inPre:
+		case r == '`':
+			inCode = !inCode
+		case inCode:
+		case inLinkURL:
+			if r == ')' {
+				inLinkURL = false
+			}
+		case r == '[':
+			inLinkText = true
+		case r == ']' && inLinkText:
+			inLinkText = false
+			if i+1 < len(runes) && runes[i+1] == '(' {
+				inLinkURL = true
+				step = 2
+			}
+		case v2 && hasPrefix(runes, i, "||"):
+			open["||"] = !open["||"]
+			step = 2
+		case r == '*' || r == '_' || (v2 && r == '~'):
+			open[string(r)] = !open[string(r)]
+		}
+
+		for j := i + 1; j <= i+step && j <= limit; j++ {
+			safe[j] = j == i+step && balanced()
+		}
+		i += step
+	}
+}
+
+// markHTMLSafe отслеживает открытые HTML теги и сущности
+func markHTMLSafe(runes []rune, limit int, safe []bool) {
+	depth := 0
+	inTag, inEntity := false, false
+	tagStart := 0
+
+	safe[0] = true
+	for i := 0; i < limit && i < len(runes); i++ {
+		switch r := runes[i]; {
+		case inTag:
+			if r == '>' {
+				inTag = false
+				tag := string(runes[tagStart : i+1])
+				switch {
+				case strings.HasPrefix(tag, "</"):
+					depth--
+				case !strings.HasSuffix(tag, "/>"):
+					depth++
+				}
+			}
+		case r == '<':
+			inTag = true
+			tagStart = i
+		case r == '&':
+			inEntity = true
+		case inEntity && (r == ';' || r == ' ' || r == '\n'):
+			inEntity = false
+		}
+
+		safe[i+1] = !inTag && !inEntity && depth <= 0
+	}
+}
+
+func hasPrefix(runes []rune, i int, prefix string) bool {
+	p := []rune(prefix)
+	if i+len(p) > len(runes) {
+		return false
+	}
+	for k, r := range p {
+		if runes[i+k] != r {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,122 @@
+package notifier
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/mdemidenko/monitoring-platform/internal/models"
+)
+
+func TestSplitMessage(t *testing.T) {
+	bold := strings.Repeat("a ", 2000) + "*" + strings.Repeat("b ", 100) + "*" + strings.Repeat(" c", 500)
+
+	tests := []struct {
+		name      string
+		text      string
+		parseMode string
+		wantParts int
+	}{
+		{name: "short text", text: "hello", wantParts: 1},
+		{name: "exactly the limit", text: strings.Repeat("x", MaxMessageLength), wantParts: 1},
+		{name: "9000 characters without spaces", text: strings.Repeat("x", 9000), wantParts: 3},
+		{name: "9000 characters in lines", text: strings.Repeat(strings.Repeat("y", 99)+"\n", 90), wantParts: 3},
+		{name: "MarkdownV2 bold across the limit", text: bold, parseMode: ParseModeMarkdownV2, wantParts: 2},
+		{name: "MarkdownV2 escaped markers", text: strings.Repeat(`a \* `, 1000), parseMode: ParseModeMarkdownV2, wantParts: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parts := splitMessage(tt.text, MaxMessageLength, tt.parseMode)
+			if len(parts) != tt.wantParts {
+				t.Fatalf("got %d parts, want %d", len(parts), tt.wantParts)
+			}
+
+			total := 0
+			for i, part := range parts {
+				if n := utf8.RuneCountInString(part); n > MaxMessageLength {
+					t.Errorf("part %d has %d characters", i, n)
+				}
+				if tt.parseMode == ParseModeMarkdownV2 && unescapedCount(part, '*')%2 != 0 {
+					t.Errorf("part %d leaves a bold entity open", i)
+				}
+				total += len(strings.Join(strings.Fields(part), ""))
+			}
+			if want := len(strings.Join(strings.Fields(tt.text), "")); total != want {
+				t.Errorf("parts hold %d non-space bytes, want %d", total, want)
+			}
+		})
+	}
+}
+
+func TestSplitMessageKeepsBoldWhole(t *testing.T) {
+	text := strings.Repeat("a ", 2000) + "*" + strings.Repeat("b ", 100) + "*"
+
+	parts := splitMessage(text, MaxMessageLength, ParseModeMarkdownV2)
+	if len(parts) != 2 {
+		t.Fatalf("got %d parts, want 2", len(parts))
+	}
+	if !strings.HasPrefix(parts[1], "*b b") || strings.Count(parts[1], "*") != 2 {
+		t.Errorf("bold entity was split: second part starts with %q", parts[1][:10])
+	}
+}
+
+// unescapedCount считает символы r, не экранированные обратной косой чертой
+func unescapedCount(s string, r rune) int {
+	count := 0
+	escaped := false
+	for _, c := range s {
+		switch {
+		case escaped:
+			escaped = false
+		case c == '\\':
+			escaped = true
+		case c == r:
+			count++
+		}
+	}
+	return count
+}
+
+func TestSendMessageSplitsLongText(t *testing.T) {
+	text := strings.Repeat(strings.Repeat("y", 99)+"\n", 90)
+	disabled := false
+
+	tests := []struct {
+		name      string
+		splitLong *bool
+		wantCalls int
+	}{
+		{name: "split by default", wantCalls: 3},
+		{name: "split disabled", splitLong: &disabled, wantCalls: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeTelegram{}
+			svc, _ := newTestService(t, testConfig(), fake)
+
+			sent, err := svc.SendMessage(context.Background(), &models.Notification{Text: text, SplitLong: tt.splitLong})
+			if err != nil {
+				t.Fatal(err)
+			}
+			calls := fake.calls()
+			if len(calls) != tt.wantCalls || len(sent) != tt.wantCalls {
+				t.Fatalf("made %d calls and returned %d messages, want %d", len(calls), len(sent), tt.wantCalls)
+			}
+
+			// Части отправляются по порядку и вместе дают исходный текст
+			var joined strings.Builder
+			for i, call := range calls {
+				joined.WriteString(call["text"].(string))
+				if i > 0 && sent[i].MessageID <= sent[i-1].MessageID {
+					t.Errorf("parts sent out of order: %+v", sent)
+				}
+			}
+			if strings.Join(strings.Fields(joined.String()), "") != strings.Join(strings.Fields(text), "") {
+				t.Error("parts do not add up to the original text")
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package notifier
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+// Stats суммарная статистика отправки за все время работы сервиса
+type Stats struct {
+	// Batches количество запущенных пакетных обработок
+	Batches int64 `json:"batches"`
+	Sent    int64 `json:"sent"`
+	Failed  int64 `json:"failed"`
+	// Suppressed уведомления, не отправленные как повторы
+	Suppressed int64 `json:"suppressed"`
+}
+
+// lifetimeStats потокобезопасные счетчики, общие для всех пакетов,
+// включая выполняющиеся одновременно
+type lifetimeStats struct {
+	batches atomic.Int64
+	sent    atomic.Int64
+	failed  atomic.Int64
+
+	suppressed atomic.Int64
+}
+
+// record учитывает результат обработки одного уведомления. Поставленные
+// в очередь паузы и отложенные тихими часами уведомления не учитываются:
+// они попадут в статистику, когда будут действительно отправлены.
+func (l *lifetimeStats) record(err error) {
+	if errors.Is(err, ErrQueued) || errors.Is(err, ErrDeferred) {
+		return
+	}
+	if errors.Is(err, ErrDuplicate) {
+		l.suppressed.Add(1)
+		return
+	}
+	if err != nil {
+		l.failed.Add(1)
+		return
+	}
+	l.sent.Add(1)
+}
+
+// Stats возвращает суммарную статистику отправки
+func (s *TelegramService) Stats() Stats {
+	return Stats{
+		Batches: s.stats.batches.Load(),
+		Sent:    s.stats.sent.Load(),
+		Failed:  s.stats.failed.Load(),
+
+		Suppressed: s.stats.suppressed.Load(),
+	}
+}
@@ -0,0 +1,60 @@
+package notifier
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"text/template"
+)
+
+// ErrTemplateNotFound возвращается, если запрошенный шаблон не загружен
+var ErrTemplateNotFound = errors.New("message template not found")
+
+// templateExt расширение файлов шаблонов в каталоге telegram.templates_dir
+const templateExt = ".tmpl"
+
+// LoadTemplates загружает шаблоны сообщений (*.tmpl) из каталога.
+// Имя шаблона — имя файла без расширения.
+func (s *TelegramService) LoadTemplates(dir string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*"+templateExt))
+	if err != nil {
+		return fmt.Errorf("failed to list templates: %w", err)
+	}
+
+	set := template.New("").Option("missingkey=error")
+	for _, file := range files {
+		name := filepath.Base(file)
+		name = name[:len(name)-len(templateExt)]
+
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to read template %s: %w", file, err)
+		}
+		if _, err := set.New(name).Parse(string(data)); err != nil {
+			return fmt.Errorf("failed to parse template %s: %w", file, err)
+		}
+	}
+
+	s.templates.Store(set)
+	log.Printf("📄 Загружено шаблонов сообщений: %d", len(files))
+
+	return nil
+}
+
+// RenderTemplate формирует текст сообщения по шаблону и данным
+func (s *TelegramService) RenderTemplate(name string, data map[string]any) (string, error) {
+	set := s.templates.Load()
+	if set == nil || set.Lookup(name) == nil {
+		return "", fmt.Errorf("%w: %s", ErrTemplateNotFound, name)
+	}
+
+	var buf bytes.Buffer
+	if err := set.ExecuteTemplate(&buf, name, data); err != nil {
+		return "", fmt.Errorf("failed to render template %s: %w", name, err)
+	}
+
+	return buf.String(), nil
+}
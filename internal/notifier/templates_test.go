@@ -0,0 +1,136 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/mdemidenko/monitoring-platform/internal/logger"
+	"github.com/mdemidenko/monitoring-platform/internal/models"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "alert.tmpl"), "{{.service}}: {{.status}} ({{len .checks}} checks)")
+	writeFile(t, filepath.Join(dir, "notes.txt"), "not a template")
+
+	svc, _ := newTestService(t, testConfig(), &fakeTelegram{})
+	if err := svc.LoadTemplates(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		data     map[string]any
+		want     string
+		wantErr  error
+	}{
+		{
+			name:     "rendered with data",
+			template: "alert",
+			data:     map[string]any{"service": "api", "status": "down", "checks": []int{1, 2}},
+			want:     "api: down (2 checks)",
+		},
+		{name: "missing template", template: "unknown", wantErr: ErrTemplateNotFound},
+		{name: "non-template file ignored", template: "notes", wantErr: ErrTemplateNotFound},
+		{name: "missing key", template: "alert", data: map[string]any{"service": "api"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := svc.RenderTemplate(tt.template, tt.data)
+			if tt.want == "" {
+				if err == nil {
+					t.Fatalf("rendered %q, want an error", got)
+				}
+				if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+					t.Errorf("error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("rendered %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSendMessageMissingTemplate(t *testing.T) {
+	fake := &fakeTelegram{}
+	svc, _ := newTestService(t, testConfig(), fake)
+
+	_, err := svc.SendMessage(context.Background(), &models.Notification{Template: "alert"})
+	if !errors.Is(err, ErrTemplateNotFound) {
+		t.Errorf("error = %v, want ErrTemplateNotFound", err)
+	}
+	if len(fake.calls()) != 0 {
+		t.Errorf("made %d Telegram calls for an unrendered template", len(fake.calls()))
+	}
+}
+
+func TestSendMessageTemplateKeepsNotification(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "alert.tmpl"), "Service {{.name}} is down")
+
+	fake := &fakeTelegram{}
+	svc, _ := newTestService(t, testConfig(), fake)
+	if err := svc.LoadTemplates(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	notification := &models.Notification{Template: "alert", Data: map[string]any{"name": "api"}}
+	if _, err := svc.SendMessage(context.Background(), notification); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := fake.calls()[0]["text"]; got != "Service api is down" {
+		t.Errorf("sent text = %q", got)
+	}
+	if notification.Template != "alert" || notification.Text != "" {
+		t.Errorf("notification was modified: template=%q text=%q", notification.Template, notification.Text)
+	}
+}
+
+// TestTemplateBatchWithStorageLogger запускается с -race: логгер хранилища
+// читает сохраненные уведомления, пока worker'ы рендерят их шаблоны
+func TestTemplateBatchWithStorageLogger(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "alert.tmpl"), "Service {{.name}} is down")
+
+	// Отправка дольше интервала логгера, чтобы он читал хранилище во время пакета
+	fake := &fakeTelegram{delay: 2 * time.Millisecond}
+	svc, storage := newTestService(t, testConfig(), fake)
+	if err := svc.LoadTemplates(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	storageLogger := logger.NewStorageLogger(storage, time.Millisecond)
+	storageLogger.Start(context.Background())
+
+	var notifications []*models.Notification
+	for i := range 40 {
+		notifications = append(notifications, &models.Notification{
+			ID:       strconv.Itoa(i),
+			Template: "alert",
+			Data:     map[string]any{"name": strconv.Itoa(i)},
+		})
+	}
+	result := svc.ProcessWithIntervals(context.Background(), notifications, 0, 4)
+
+	if err := storageLogger.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if result.SuccessCount != len(notifications) {
+		t.Errorf("SuccessCount = %d, want %d", result.SuccessCount, len(notifications))
+	}
+	if got := len(storage.GetSentNotifications()); got != len(notifications) {
+		t.Errorf("stored %d sent notifications, want %d", got, len(notifications))
+	}
+}
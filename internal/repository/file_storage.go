@@ -2,9 +2,15 @@ package repository
 
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log"
 	"os"
+	"time"
 
 	"github.com/mdemidenko/monitoring-platform/internal/models"
 )
@@ -15,33 +21,254 @@ type Repository interface {
 }
 
 type repository struct {
-	inputFile  string
-	outputFile string
+	// source источник входных данных: файл inputFile или URL
+	source        Source
+	sourceTimeout time.Duration
+	outputFile    string
+	lenient       bool
+	rotate        bool
+	keepFiles     int
+	// fieldMapping переименование ключей входных записей в ключи models.Service
+	fieldMapping map[string]string
+	// keepOnEmpty не перезаписывать файл результатов, если результатов нет
+	keepOnEmpty bool
+	// lockWait сколько ждать блокировку файла результатов (0 — не ждать)
+	lockWait time.Duration
+	// sinks дополнительные получатели результатов (WithSinks)
+	sinks []Sink
+	// sinkTimeout ограничение времени записи во все получатели (0 — без ограничения)
+	sinkTimeout time.Duration
 }
 
-func NewRepository(inputFile, outputFile string) Repository {
-	return &repository{
-		inputFile:  inputFile,
-		outputFile: outputFile,
+// Option настраивает репозиторий при создании
+type Option func(*repository)
+
+// WithLenientParsing включает пропуск некорректных записей во входном файле
+// вместо прерывания чтения на первой ошибке
+func WithLenientParsing(lenient bool) Option {
+	return func(r *repository) {
+		r.lenient = lenient
+	}
+}
+
+// WithFieldMapping задает соответствие ключей входного JSON полям models.Service
+// (ключ источника -> json ключ Service, например "service_name" -> "name")
+func WithFieldMapping(mapping map[string]string) Option {
+	return func(r *repository) {
+		r.fieldMapping = mapping
+	}
+}
+
+// WithKeepOnEmpty оставляет предыдущий файл результатов, если новых результатов
+// нет. По умолчанию пустой запуск записывает пустой массив [].
+func WithKeepOnEmpty(keep bool) Option {
+	return func(r *repository) {
+		r.keepOnEmpty = keep
+	}
+}
+
+func NewRepository(inputFile, outputFile string, opts ...Option) Repository {
+	r := &repository{
+		source:        NewSource(inputFile),
+		sourceTimeout: defaultSourceTimeout,
+		outputFile:    outputFile,
+		sinkTimeout:   defaultSinkTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(r)
 	}
+
+	return r
 }
 
 func (r *repository) GetServices() ([]models.Service, error) {
-	data, err := os.ReadFile(r.inputFile)
+	ctx := context.Background()
+	if r.sourceTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.sourceTimeout)
+		defer cancel()
+	}
+
+	reader, err := r.source.Open(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("ошибка чтения файла: %w", err)
+		return nil, fmt.Errorf("ошибка чтения %s: %w", r.source, err)
 	}
+	defer reader.Close()
 
-	var services []models.Service
-	if err := json.Unmarshal(data, &services); err != nil {
+	lines := newLineReader(reader)
+	services, err := r.decodeServices(lines)
+	if lines.err != nil {
+		return nil, fmt.Errorf("ошибка чтения %s: %w", r.source, lines.err)
+	}
+	return services, err
+}
+
+// decodeServices разбирает JSON массив сервисов потоком по одной записи, чтобы
+// в случае ошибки сообщить номер записи, смещение и строку в файле
+func (r *repository) decodeServices(lines *lineReader) ([]models.Service, error) {
+	decoder := json.NewDecoder(lines)
+
+	token, err := decoder.Token()
+	if err != nil {
 		return nil, fmt.Errorf("ошибка парсинга JSON: %w", err)
 	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return nil, fmt.Errorf("ошибка парсинга JSON: ожидался массив сервисов")
+	}
+
+	var services []models.Service
+	skipped := 0
+
+	for index := 0; decoder.More(); index++ {
+		offset := decoder.InputOffset()
+		// Предыдущие записи разобраны, их строки больше не нужны
+		lines.advance(offset)
+
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			// Синтаксическая ошибка: продолжить чтение потока невозможно
+			errOffset := decoder.InputOffset()
+			var syntaxErr *json.SyntaxError
+			if errors.As(err, &syntaxErr) {
+				errOffset = syntaxErr.Offset
+			}
+			return nil, fmt.Errorf("ошибка парсинга JSON в записи %d (смещение %d, строка %d): %w",
+				index, errOffset, lines.lineAt(errOffset), err)
+		}
+
+		var service models.Service
+		if err := r.unmarshalService(raw, &service); err != nil {
+			if !r.lenient {
+				return nil, fmt.Errorf("ошибка парсинга JSON в записи %d (смещение %d, строка %d): %w",
+					index, offset, lines.lineAt(offset), err)
+			}
+			log.Printf("⚠️  Пропущена некорректная запись %d (строка %d): %v", index, lines.lineAt(offset), err)
+			skipped++
+			continue
+		}
+
+		services = append(services, service)
+	}
+
+	if _, err := decoder.Token(); err != nil {
+		return nil, fmt.Errorf("ошибка парсинга JSON (смещение %d, строка %d): %w",
+			decoder.InputOffset(), lines.lineAt(decoder.InputOffset()), err)
+	}
+
+	if skipped > 0 {
+		log.Printf("⚠️  Пропущено некорректных записей: %d", skipped)
+	}
 
 	return services, nil
 }
 
-func (r *repository) SaveResults(results []models.Result) error {
-    file, err := os.Create(r.outputFile)
+// unmarshalService разбирает запись сервиса с учетом переименования ключей
+func (r *repository) unmarshalService(raw json.RawMessage, service *models.Service) error {
+	if len(r.fieldMapping) == 0 {
+		return json.Unmarshal(raw, service)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return err
+	}
+
+	// Сначала ключи без переименования, затем переименованные: если в записи
+	// есть и исходный ключ, и целевой, побеждает значение исходного ключа
+	mapped := make(map[string]json.RawMessage, len(fields))
+	for key, value := range fields {
+		if _, ok := r.fieldMapping[key]; !ok {
+			mapped[key] = value
+		}
+	}
+	renamedFrom := make(map[string]string)
+	for key, value := range fields {
+		target, ok := r.fieldMapping[key]
+		if !ok {
+			continue
+		}
+		if other, dup := renamedFrom[target]; dup {
+			first, second := min(key, other), max(key, other)
+			return fmt.Errorf("ключи %q и %q соответствуют одному полю %q", first, second, target)
+		}
+		renamedFrom[target] = key
+		mapped[target] = value
+	}
+
+	data, err := json.Marshal(mapped)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, service)
+}
+
+// lineReader пропускает через себя входной поток и хранит только
+// еще не разобранный хвост, чтобы вычислять номера строк по смещению
+// без чтения всего входа в память
+type lineReader struct {
+	r io.Reader
+	// buf прочитанные данные начиная со смещения start
+	buf   []byte
+	start int64
+	// line номер строки (с 1) на смещении start
+	line int
+	// err ошибка чтения источника (кроме io.EOF)
+	err error
+}
+
+func newLineReader(r io.Reader) *lineReader {
+	return &lineReader{r: r, line: 1}
+}
+
+func (l *lineReader) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	l.buf = append(l.buf, p[:n]...)
+	if err != nil && err != io.EOF {
+		l.err = err
+	}
+	return n, err
+}
+
+// advance отбрасывает данные до offset, запоминая число пройденных строк
+func (l *lineReader) advance(offset int64) {
+	rel := min(max(offset-l.start, 0), int64(len(l.buf)))
+	l.line += bytes.Count(l.buf[:rel], []byte("\n"))
+	l.buf = l.buf[rel:]
+	l.start += rel
+}
+
+// lineAt возвращает номер строки (с 1) для смещения в потоке,
+// пропуская разделители перед началом записи
+func (l *lineReader) lineAt(offset int64) int {
+	rel := min(max(offset-l.start, 0), int64(len(l.buf)))
+	for rel < int64(len(l.buf)) && bytes.IndexByte([]byte(" \t\r\n,"), l.buf[rel]) >= 0 {
+		rel++
+	}
+	return l.line + bytes.Count(l.buf[:rel], []byte("\n"))
+}
+
+// saveFile записывает результаты в outputFile
+func (r *repository) saveFile(results []models.Result) error {
+    if len(results) == 0 {
+        if r.keepOnEmpty {
+            log.Printf("⚠️  Результатов нет, файл %s не перезаписывается", r.outputFile)
+            return nil
+        }
+        // Пустой массив вместо null: потребители видят, что совпадений нет
+        results = []models.Result{}
+    }
+
+    // Блокировка защищает от одновременной записи несколькими запусками
+    unlock, err := r.acquireLock()
+    if err != nil {
+        return err
+    }
+    defer unlock()
+
+    outputFile := r.outputPath()
+
+    file, err := os.Create(outputFile)
     if err != nil {
         return fmt.Errorf("ошибка создания файла: %w", err)
     }
@@ -62,6 +289,7 @@ func (r *repository) SaveResults(results []models.Result) error {
     if closeErr != nil {
         return fmt.Errorf("ошибка закрытия файла: %w", closeErr)
     }
-    
-    return nil
+
+    // Удаляем устаревшие файлы, если включена ротация
+    return r.pruneRotated()
 }
\ No newline at end of file
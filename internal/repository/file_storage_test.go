@@ -0,0 +1,225 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mdemidenko/monitoring-platform/internal/models"
+)
+
+func writeInput(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "services.json")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestGetServicesFieldMapping(t *testing.T) {
+	mapping := map[string]string{"service_name": "name", "alias": "name", "line": "businessLine"}
+
+	tests := []struct {
+		name     string
+		input    string
+		wantName string
+		wantLine string
+		wantErr  string
+	}{
+		{
+			name:     "renamed keys",
+			input:    `[{"id": 1, "service_name": "api", "line": "monitor"}]`,
+			wantName: "api",
+			wantLine: "monitor",
+		},
+		{
+			name:     "mapped key wins over target key",
+			input:    `[{"id": 1, "name": "old", "service_name": "api", "businessLine": "x", "line": "monitor"}]`,
+			wantName: "api",
+			wantLine: "monitor",
+		},
+		{
+			name:    "two keys mapped to one field",
+			input:   `[{"id": 1, "service_name": "api", "alias": "other"}]`,
+			wantErr: `"alias" и "service_name"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Порядок обхода map случаен, поэтому проверяем несколько раз
+			for range 20 {
+				repo := NewRepository(writeInput(t, tt.input), "", WithFieldMapping(mapping))
+				services, err := repo.GetServices()
+				if tt.wantErr != "" {
+					if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+						t.Fatalf("error = %v, want %q", err, tt.wantErr)
+					}
+					continue
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				if services[0].Name != tt.wantName || services[0].BusinessLine != tt.wantLine {
+					t.Fatalf("service = %+v, want name %q and line %q", services[0], tt.wantName, tt.wantLine)
+				}
+			}
+		})
+	}
+}
+
+func TestGetServicesErrorLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		wantLine string
+	}{
+		{name: "invalid field type", input: "[\n {\"id\": 1},\n\n {\"id\": \"x\"}\n]", wantLine: "строка 4"},
+		{name: "syntax error", input: "[\n {\"id\": 1},\n {\"id\": 2,,}\n]", wantLine: "строка 3"},
+		{name: "unterminated array", input: "[\n {\"id\": 1},\n {\"id\": 2}\n", wantLine: "строка 4"},
+		{name: "long input", input: "[\n" + strings.Repeat(" {\"id\": 1, \"name\": \"a\"},\n", 5000) + " {\"id\": []}\n]", wantLine: "строка 5002"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewRepository(writeInput(t, tt.input), "").GetServices()
+			if err == nil || !strings.Contains(err.Error(), tt.wantLine) {
+				t.Errorf("error = %v, want %q", err, tt.wantLine)
+			}
+		})
+	}
+}
+
+func TestGetServicesLenient(t *testing.T) {
+	input := "[\n {\"id\": 1, \"name\": \"api\"},\n {\"id\": \"x\"},\n {\"id\": 3, \"name\": \"web\"}\n]"
+
+	services, err := NewRepository(writeInput(t, input), "", WithLenientParsing(true)).GetServices()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(services) != 2 || services[0].Name != "api" || services[1].Name != "web" {
+		t.Errorf("services = %+v, want the two valid records", services)
+	}
+}
+
+// failingReader отдает часть данных и затем ошибку чтения
+type failingReader struct {
+	data io.Reader
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	n, err := r.data.Read(p)
+	if err == io.EOF {
+		return n, errors.New("connection reset")
+	}
+	return n, err
+}
+
+func (r *failingReader) Close() error { return nil }
+
+type readerSource struct {
+	reader io.ReadCloser
+}
+
+func (s readerSource) Open(context.Context) (io.ReadCloser, error) { return s.reader, nil }
+func (s readerSource) String() string                              { return "test source" }
+
+func TestGetServicesReadError(t *testing.T) {
+	source := readerSource{reader: &failingReader{data: strings.NewReader(`[{"id": 1}, {"id": 2`)}}
+	_, err := NewRepository("", "", WithSource(source)).GetServices()
+	if err == nil || !strings.Contains(err.Error(), "ошибка чтения test source") || !strings.Contains(err.Error(), "connection reset") {
+		t.Errorf("error = %v, want a read error", err)
+	}
+}
+
+func TestGetServicesHTTPSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"id": 1, "name": "api"}, {"id": 2, "name": "web"}]`))
+	}))
+	defer server.Close()
+
+	services, err := NewRepository(server.URL, "").GetServices()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(services) != 2 || services[1].Name != "web" {
+		t.Errorf("services = %+v", services)
+	}
+}
+
+func TestGetServicesHTTPSourceStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "maintenance", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	_, err := NewRepository(server.URL, "").GetServices()
+	if err == nil || !strings.Contains(err.Error(), "503") {
+		t.Errorf("error = %v, want the response status", err)
+	}
+}
+
+func TestGetServicesHTTPSourceTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	start := time.Now()
+	_, err := NewRepository(server.URL, "", WithSourceTimeout(50*time.Millisecond)).GetServices()
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("error = %v, want a deadline error", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("GetServices returned after %v", elapsed)
+	}
+}
+
+func TestSaveResultsEmpty(t *testing.T) {
+	tests := []struct {
+		name        string
+		keepOnEmpty bool
+		want        string
+	}{
+		{name: "empty array replaces stale results", want: "[]"},
+		{name: "keep on empty leaves the old file", keepOnEmpty: true, want: `[{"id":1,"name":"stale","tenant":""}]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := filepath.Join(t.TempDir(), "out.json")
+			if err := os.WriteFile(output, []byte(`[{"id":1,"name":"stale","tenant":""}]`), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			repo := NewRepository("", output, WithKeepOnEmpty(tt.keepOnEmpty))
+			for _, results := range [][]models.Result{nil, {}} {
+				if err := repo.SaveResults(results); err != nil {
+					t.Fatal(err)
+				}
+
+				data, err := os.ReadFile(output)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got := strings.TrimSpace(string(data)); got != tt.want {
+					t.Errorf("SaveResults(%#v) wrote %s, want %s", results, got, tt.want)
+				}
+			}
+		})
+	}
+}
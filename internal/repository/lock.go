@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrLocked возвращается, когда файл результатов занят другим процессом
+var ErrLocked = errors.New("файл результатов заблокирован другим процессом")
+
+// lockRetryInterval период повторных попыток захвата блокировки в режиме ожидания
+const lockRetryInterval = 100 * time.Millisecond
+
+// WithLockWait задает, сколько ждать освобождения блокировки файла результатов.
+// 0 — не ждать и сразу вернуть ErrLocked.
+func WithLockWait(wait time.Duration) Option {
+	return func(r *repository) {
+		r.lockWait = wait
+	}
+}
+
+// lockPath возвращает путь к файлу блокировки результатов
+func (r *repository) lockPath() string {
+	return r.outputFile + ".lock"
+}
+
+// acquireLock создает файл блокировки рядом с файлом результатов.
+// Файл создается с O_EXCL, поэтому его может создать только один процесс;
+// внутрь записывается PID владельца. Возвращает функцию освобождения.
+func (r *repository) acquireLock() (func(), error) {
+	path := r.lockPath()
+	deadline := time.Now().Add(r.lockWait)
+
+	for {
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if err == nil {
+			_, writeErr := file.WriteString(strconv.Itoa(os.Getpid()))
+			closeErr := file.Close()
+			if err := errors.Join(writeErr, closeErr); err != nil {
+				os.Remove(path)
+				return nil, fmt.Errorf("ошибка записи файла блокировки %s: %w", path, err)
+			}
+			return func() { os.Remove(path) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("ошибка создания файла блокировки %s: %w", path, err)
+		}
+
+		if !time.Now().Before(deadline) {
+			owner, _ := os.ReadFile(path)
+			return nil, fmt.Errorf("%w: %s (PID %s); если процесс завершен, удалите файл вручную",
+				ErrLocked, path, strings.TrimSpace(string(owner)))
+		}
+		time.Sleep(lockRetryInterval)
+	}
+}
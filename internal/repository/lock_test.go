@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mdemidenko/monitoring-platform/internal/models"
+)
+
+func TestSaveResultsLocked(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "out.json")
+	lock := output + ".lock"
+	if err := os.WriteFile(lock, []byte("12345"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := NewRepository("", output).SaveResults([]models.Result{{ID: 1, Name: "api"}})
+	if !errors.Is(err, ErrLocked) || !strings.Contains(err.Error(), "PID 12345") {
+		t.Errorf("error = %v, want ErrLocked with the owner PID", err)
+	}
+	if fileExists(output) {
+		t.Error("results written while the lock was held")
+	}
+	if !fileExists(lock) {
+		t.Error("foreign lock file removed")
+	}
+}
+
+func TestSaveResultsLockWait(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "out.json")
+	lock := output + ".lock"
+	if err := os.WriteFile(lock, []byte("12345"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// Другой процесс освобождает блокировку, пока мы ждем
+	time.AfterFunc(150*time.Millisecond, func() { os.Remove(lock) })
+
+	repo := NewRepository("", output, WithLockWait(5*time.Second))
+	if err := repo.SaveResults([]models.Result{{ID: 1, Name: "api"}}); err != nil {
+		t.Fatal(err)
+	}
+	if !fileExists(output) {
+		t.Error("results not written after the lock was released")
+	}
+	if fileExists(lock) {
+		t.Error("own lock file left behind")
+	}
+}
+
+func TestSaveResultsLockWaitTimeout(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "out.json")
+	if err := os.WriteFile(output+".lock", []byte("12345"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	err := NewRepository("", output, WithLockWait(250*time.Millisecond)).SaveResults(nil)
+	if !errors.Is(err, ErrLocked) {
+		t.Errorf("error = %v, want ErrLocked", err)
+	}
+	if elapsed := time.Since(start); elapsed < 250*time.Millisecond {
+		t.Errorf("gave up after %v, before the lock wait", elapsed)
+	}
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// rotationTimeLayout формат метки времени в имени файла результатов
+const rotationTimeLayout = "20060102-150405"
+
+// WithRotation включает запись результатов в файлы с меткой времени.
+// outputFile используется как шаблон имени, keep ограничивает количество
+// хранимых файлов (0 — хранить все).
+func WithRotation(keep int) Option {
+	return func(r *repository) {
+		r.rotate = true
+		r.keepFiles = keep
+	}
+}
+
+// outputPath возвращает путь для очередной записи результатов
+func (r *repository) outputPath() string {
+	if !r.rotate {
+		return r.outputFile
+	}
+
+	base, ext := splitExt(r.outputFile)
+	stamp := time.Now().Format(rotationTimeLayout)
+
+	path := fmt.Sprintf("%s-%s%s", base, stamp, ext)
+	for i := 1; fileExists(path); i++ {
+		path = fmt.Sprintf("%s-%s-%d%s", base, stamp, i, ext)
+	}
+
+	return path
+}
+
+// pruneRotated удаляет самые старые файлы результатов сверх лимита keepFiles
+func (r *repository) pruneRotated() error {
+	if !r.rotate || r.keepFiles <= 0 {
+		return nil
+	}
+
+	files, err := r.rotatedFiles()
+	if err != nil {
+		return err
+	}
+	if len(files) <= r.keepFiles {
+		return nil
+	}
+
+	for _, file := range files[:len(files)-r.keepFiles] {
+		if err := os.Remove(file.path); err != nil {
+			return fmt.Errorf("ошибка удаления старого файла %s: %w", file.path, err)
+		}
+		log.Printf("🗑️  Удален старый файл результатов: %s", file.path)
+	}
+
+	return nil
+}
+
+type rotatedFile struct {
+	path    string
+	modTime time.Time
+	// stamp и seq метка времени и номер файла внутри одной секунды из имени
+	stamp string
+	seq   int
+}
+
+// rotatedFiles возвращает файлы результатов, созданные ротацией, от старых к новым
+func (r *repository) rotatedFiles() ([]rotatedFile, error) {
+	base, ext := splitExt(r.outputFile)
+	pattern := regexp.MustCompile("^" + regexp.QuoteMeta(filepath.Base(base)) +
+		`-(\d{8}-\d{6})(?:-(\d+))?` + regexp.QuoteMeta(ext) + "$")
+
+	entries, err := os.ReadDir(filepath.Dir(r.outputFile))
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения каталога результатов: %w", err)
+	}
+
+	var files []rotatedFile
+	for _, entry := range entries {
+		match := pattern.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, fmt.Errorf("ошибка чтения файла %s: %w", entry.Name(), err)
+		}
+		seq, _ := strconv.Atoi(match[2])
+		files = append(files, rotatedFile{
+			path:    filepath.Join(filepath.Dir(r.outputFile), entry.Name()),
+			modTime: info.ModTime(),
+			stamp:   match[1],
+			seq:     seq,
+		})
+	}
+
+	// Время изменения файла может совпадать у записей подряд, тогда порядок
+	// определяется именем: файл без номера создан раньше файлов -1, -2, ...
+	sort.Slice(files, func(i, j int) bool {
+		a, b := files[i], files[j]
+		switch {
+		case !a.modTime.Equal(b.modTime):
+			return a.modTime.Before(b.modTime)
+		case a.stamp != b.stamp:
+			return a.stamp < b.stamp
+		default:
+			return a.seq < b.seq
+		}
+	})
+
+	return files, nil
+}
+
+func splitExt(path string) (base, ext string) {
+	ext = filepath.Ext(path)
+	return strings.TrimSuffix(path, ext), ext
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
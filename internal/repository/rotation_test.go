@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mdemidenko/monitoring-platform/internal/models"
+)
+
+func TestRotationKeepsNewestFiles(t *testing.T) {
+	tests := []struct {
+		name      string
+		keep      int
+		runs      int
+		wantFiles int
+	}{
+		{name: "below the limit", keep: 3, runs: 2, wantFiles: 2},
+		{name: "old files pruned", keep: 2, runs: 5, wantFiles: 2},
+		{name: "keep all", keep: 0, runs: 4, wantFiles: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := filepath.Join(t.TempDir(), "out.json")
+			repo := NewRepository("", output, WithRotation(tt.keep))
+			for run := 1; run <= tt.runs; run++ {
+				if err := repo.SaveResults([]models.Result{{ID: run, Name: "run"}}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			files, err := repo.(*repository).rotatedFiles()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(files) != tt.wantFiles {
+				t.Fatalf("%d rotated files left, want %d", len(files), tt.wantFiles)
+			}
+			if fileExists(output) {
+				t.Errorf("rotation wrote the unrotated file %s", output)
+			}
+
+			// Остаются последние запуски, от старых к новым
+			for i, file := range files {
+				wantID := tt.runs - tt.wantFiles + i + 1
+				if id := resultID(t, file.path); id != wantID {
+					t.Errorf("file %d (%s) holds run %d, want %d", i, filepath.Base(file.path), id, wantID)
+				}
+			}
+		})
+	}
+}
+
+func TestRotatedFilesOrderWithinOneSecond(t *testing.T) {
+	dir := t.TempDir()
+	repo := NewRepository("", filepath.Join(dir, "out.json"), WithRotation(0)).(*repository)
+
+	// Одинаковое время изменения: порядок задается именем
+	modTime := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	names := []string{"out-20260101-120000.json", "out-20260101-120000-1.json", "out-20260101-120000-2.json", "out-20260101-120001.json"}
+	for i := len(names) - 1; i >= 0; i-- {
+		path := filepath.Join(dir, names[i])
+		if err := os.WriteFile(path, []byte("[]"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Файлы с другим шаблоном имени не затрагиваются
+	if err := os.WriteFile(filepath.Join(dir, "out-latest.json"), []byte("[]"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := repo.rotatedFiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != len(names) {
+		t.Fatalf("got %d rotated files, want %d", len(files), len(names))
+	}
+	for i, file := range files {
+		if filepath.Base(file.path) != names[i] {
+			t.Errorf("file %d = %s, want %s", i, filepath.Base(file.path), names[i])
+		}
+	}
+}
+
+// resultID возвращает ID единственного результата в файле
+func resultID(t *testing.T, path string) int {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var results []models.Result
+	if err := json.Unmarshal(data, &results); err != nil || len(results) != 1 {
+		t.Fatalf("%s: %v, %d results", path, err, len(results))
+	}
+	return results[0].ID
+}
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/mdemidenko/monitoring-platform/internal/models"
+)
+
+const (
+	// defaultSinkTimeout ограничение времени записи результатов во все получатели
+	// по умолчанию (WithSinkTimeout)
+	defaultSinkTimeout = 30 * time.Second
+	// defaultSinkBatchSize число результатов в одном запросе HTTP получателя
+	defaultSinkBatchSize = 500
+)
+
+// Sink получатель отфильтрованных результатов
+type Sink interface {
+	// Write передает результаты получателю
+	Write(ctx context.Context, results []models.Result) error
+	// String описание получателя для логов и ошибок
+	String() string
+}
+
+// WithSinks добавляет получателей результатов к файлу outputFile.
+// SaveResults записывает результаты во все получатели одновременно.
+func WithSinks(sinks ...Sink) Option {
+	return func(r *repository) {
+		r.sinks = append(r.sinks, sinks...)
+	}
+}
+
+// WithSinkTimeout ограничивает время записи результатов во все получатели
+// (0 — без ограничения)
+func WithSinkTimeout(timeout time.Duration) Option {
+	return func(r *repository) {
+		r.sinkTimeout = timeout
+	}
+}
+
+// SaveResults записывает результаты в файл и во все дополнительные получатели.
+// Ошибки получателей не прерывают запись в остальные и возвращаются вместе.
+func (r *repository) SaveResults(results []models.Result) error {
+	ctx := context.Background()
+	if r.sinkTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.sinkTimeout)
+		defer cancel()
+	}
+
+	sinks := append([]Sink{fileSink{repo: r}}, r.sinks...)
+	errs := make([]error, len(sinks))
+
+	var wg sync.WaitGroup
+	for i, sink := range sinks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := sink.Write(ctx, results); err != nil {
+				errs[i] = fmt.Errorf("%s: %w", sink, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// fileSink запись результатов в файл репозитория с блокировкой и ротацией
+type fileSink struct {
+	repo *repository
+}
+
+func (s fileSink) Write(ctx context.Context, results []models.Result) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return s.repo.saveFile(results)
+}
+
+func (s fileSink) String() string {
+	return s.repo.outputFile
+}
+
+// httpSink отправляет результаты POST запросами пакетами по batchSize
+type httpSink struct {
+	url       string
+	batchSize int
+	client    *http.Client
+}
+
+// NewHTTPSink создает получателя, отправляющего результаты JSON массивами
+// POST запросами на url. batchSize <= 0 — размер пакета по умолчанию.
+// Пустые результаты не отправляются.
+func NewHTTPSink(url string, batchSize int) Sink {
+	if batchSize <= 0 {
+		batchSize = defaultSinkBatchSize
+	}
+	return &httpSink{url: url, batchSize: batchSize, client: http.DefaultClient}
+}
+
+func (s *httpSink) Write(ctx context.Context, results []models.Result) error {
+	for start := 0; start < len(results); start += s.batchSize {
+		end := min(start+s.batchSize, len(results))
+		if err := s.post(ctx, results[start:end]); err != nil {
+			return fmt.Errorf("пакет %d-%d: %w", start+1, end, err)
+		}
+	}
+	return nil
+}
+
+func (s *httpSink) post(ctx context.Context, batch []models.Result) error {
+	body, err := json.Marshal(batch)
+	if err != nil {
+		return fmt.Errorf("ошибка записи JSON: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("ошибка создания запроса: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// Дочитываем тело, чтобы соединение вернулось в пул
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("сервер вернул %s", resp.Status)
+	}
+	return nil
+}
+
+func (s *httpSink) String() string {
+	return s.url
+}
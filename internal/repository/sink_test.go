@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mdemidenko/monitoring-platform/internal/models"
+)
+
+func TestSaveResultsSinks(t *testing.T) {
+	results := []models.Result{
+		{ID: 1, Name: "api", Tenant: "a"},
+		{ID: 2, Name: "web", Tenant: "a"},
+		{ID: 3, Name: "db", Tenant: "b"},
+	}
+
+	var mu sync.Mutex
+	var batches [][]models.Result
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var batch []models.Result
+		if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		batches = append(batches, batch)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	output := filepath.Join(t.TempDir(), "out.json")
+	repo := NewRepository("", output, WithSinks(NewHTTPSink(server.URL, 2)))
+	if err := repo.SaveResults(results); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var saved []models.Result
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatal(err)
+	}
+	if len(saved) != len(results) {
+		t.Errorf("file has %d results, want %d", len(saved), len(results))
+	}
+
+	if len(batches) != 2 || len(batches[0]) != 2 || len(batches[1]) != 1 {
+		t.Fatalf("HTTP sink batches = %v, want sizes 2 and 1", batches)
+	}
+	if batches[1][0].Name != "db" {
+		t.Errorf("last batch = %+v", batches[1])
+	}
+}
+
+func TestSaveResultsSinkErrors(t *testing.T) {
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	output := filepath.Join(t.TempDir(), "out.json")
+	repo := NewRepository("", output, WithSinks(NewHTTPSink(failing.URL, 0)))
+	err := repo.SaveResults([]models.Result{{ID: 1, Name: "api"}})
+	if err == nil || !strings.Contains(err.Error(), failing.URL) || !strings.Contains(err.Error(), "500") {
+		t.Fatalf("error = %v, want the failing sink reported", err)
+	}
+
+	// Ошибка одного получателя не мешает записи в файл
+	if _, err := os.Stat(output); err != nil {
+		t.Errorf("output file was not written: %v", err)
+	}
+}
+
+func TestHTTPSinkCancellation(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := NewHTTPSink(server.URL, 0).Write(ctx, []models.Result{{ID: 1}})
+	if err == nil || !strings.Contains(err.Error(), context.DeadlineExceeded.Error()) {
+		t.Errorf("error = %v, want deadline exceeded", err)
+	}
+}
+
+func TestSaveResultsSinkTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	output := filepath.Join(t.TempDir(), "out.json")
+	repo := NewRepository("", output, WithSinks(NewHTTPSink(server.URL, 0)), WithSinkTimeout(20*time.Millisecond))
+
+	start := time.Now()
+	err := repo.SaveResults([]models.Result{{ID: 1, Name: "api"}})
+	if err == nil || !strings.Contains(err.Error(), context.DeadlineExceeded.Error()) {
+		t.Fatalf("error = %v, want deadline exceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("SaveResults took %s, want it bounded by the sink timeout", elapsed)
+	}
+	if _, err := os.Stat(output); err != nil {
+		t.Errorf("output file was not written: %v", err)
+	}
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// defaultSourceTimeout ограничение времени загрузки входных данных по HTTP
+const defaultSourceTimeout = 30 * time.Second
+
+// Source источник входного JSON массива сервисов
+type Source interface {
+	// Open открывает поток с данными; закрыть его должен вызывающий код
+	Open(ctx context.Context) (io.ReadCloser, error)
+	// String описание источника для логов и ошибок
+	String() string
+}
+
+// NewSource выбирает источник по расположению: http(s) URL или путь к файлу
+func NewSource(location string) Source {
+	if strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://") {
+		return &httpSource{url: location, client: http.DefaultClient}
+	}
+	return fileSource{path: location}
+}
+
+// WithSource заменяет источник входных данных, заданный в NewRepository
+func WithSource(source Source) Option {
+	return func(r *repository) {
+		r.source = source
+	}
+}
+
+// WithSourceTimeout ограничивает время чтения входных данных (0 — без ограничения)
+func WithSourceTimeout(timeout time.Duration) Option {
+	return func(r *repository) {
+		r.sourceTimeout = timeout
+	}
+}
+
+// fileSource читает сервисы из локального файла
+type fileSource struct {
+	path string
+}
+
+func (s fileSource) Open(ctx context.Context) (io.ReadCloser, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return os.Open(s.path)
+}
+
+func (s fileSource) String() string {
+	return s.path
+}
+
+// httpSource загружает сервисы GET запросом по URL
+type httpSource struct {
+	url    string
+	client *http.Client
+}
+
+func (s *httpSource) Open(ctx context.Context) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка создания запроса: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("сервер вернул %s", resp.Status)
+	}
+
+	return resp.Body, nil
+}
+
+func (s *httpSource) String() string {
+	return s.url
+}
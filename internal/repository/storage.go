@@ -1,45 +1,252 @@
 package repository
 
 import (
+	"context"
 	"fmt"
+	"log"
+	"reflect"
+	"sync"
+	"time"
+
 	"github.com/mdemidenko/monitoring-platform/internal/models"
 )
 
 type Storage interface {
 	Store(entity any) error
+	// StoreBatch сохраняет несколько сущностей под одной блокировкой
+	StoreBatch(entities []any) error
 	GetNotifications() []*models.Notification
 	GetSentNotifications() []*models.SentNotification
+	GetNotificationByID(id string) (*models.Notification, bool)
+	GetSentNotificationsByNotificationID(id string) []*models.SentNotification
 }
 
+// MemoryStorage хранит сущности в памяти, раскладывая их по корзинам
+// по типу. Новые типы добавляются через RegisterEntity без изменения Store.
 type MemoryStorage struct {
-	notifications     []*models.Notification
-	sentNotifications []*models.SentNotification
+	mu                sync.RWMutex
+	buckets           map[reflect.Type]*bucket
+	notificationsByID map[string]*models.Notification
+}
+
+// bucket сущности одного типа в порядке сохранения
+type bucket struct {
+	items []storedItem
+	// dropped сколько сущностей удалено из начала корзины при сжатии
+	dropped int
+	// onStore вызывается под блокировкой хранилища, например для индексов
+	onStore func(entity any)
 }
 
 func NewMemoryStorage() *MemoryStorage {
-	return &MemoryStorage{
-		notifications:     make([]*models.Notification, 0),
-		sentNotifications: make([]*models.SentNotification, 0),
+	m := &MemoryStorage{
+		buckets:           make(map[reflect.Type]*bucket),
+		notificationsByID: make(map[string]*models.Notification),
+	}
+
+	RegisterEntity(m, func(n *models.Notification) {
+		if n.ID != "" {
+			m.notificationsByID[n.ID] = n
+		}
+	})
+	RegisterEntity[*models.SentNotification](m)
+
+	return m
+}
+
+// RegisterEntity разрешает хранить в m сущности типа T. Необязательный
+// onStore вызывается при каждом сохранении под блокировкой хранилища.
+// Повторная регистрация типа заменяет обработчик, сохраняя данные.
+func RegisterEntity[T any](m *MemoryStorage, onStore ...func(T)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	t := reflect.TypeFor[T]()
+	b, ok := m.buckets[t]
+	if !ok {
+		b = &bucket{}
+		m.buckets[t] = b
+	}
+
+	b.onStore = nil
+	if len(onStore) > 0 {
+		hook := onStore[0]
+		b.onStore = func(entity any) { hook(entity.(T)) }
+	}
+}
+
+// storedItem сущность и время ее сохранения (для Retention.MaxAge)
+type storedItem struct {
+	entity   any
+	storedAt time.Time
+}
+
+// Entities возвращает копию всех сохраненных сущностей типа T
+func Entities[T any](m *MemoryStorage) []T {
+	entities, _ := EntitiesSince[T](m, 0)
+	return entities
+}
+
+// EntitiesSince возвращает сущности типа T с порядковым номером сохранения
+// не меньше offset и номер, с которого продолжать следующий вызов. Номера
+// не сдвигаются при сжатии, поэтому удаленные сущности просто пропускаются.
+func EntitiesSince[T any](m *MemoryStorage, offset int) ([]T, int) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	b, ok := m.buckets[reflect.TypeFor[T]()]
+	if !ok {
+		return nil, offset
+	}
+
+	next := b.dropped + len(b.items)
+	start := max(offset-b.dropped, 0)
+	if start >= len(b.items) {
+		return nil, next
 	}
+
+	entities := make([]T, 0, len(b.items)-start)
+	for _, item := range b.items[start:] {
+		entities = append(entities, item.entity.(T))
+	}
+	return entities, next
 }
 
 func (m *MemoryStorage) Store(entity any) error {
-	switch v := entity.(type) {
-	case *models.Notification:
-		m.notifications = append(m.notifications, v)
-	case *models.SentNotification:
-		m.sentNotifications = append(m.sentNotifications, v)
-	default:
-		return fmt.Errorf("unsupported entity type: %T", v)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	b, ok := m.buckets[reflect.TypeOf(entity)]
+	if !ok {
+		return fmt.Errorf("unsupported entity type: %T", entity)
+	}
+
+	b.items = append(b.items, storedItem{entity: entity, storedAt: time.Now()})
+	if b.onStore != nil {
+		b.onStore(entity)
+	}
+
+	return nil
+}
+
+// StoreBatch сохраняет сущности по порядку, захватывая блокировку один раз.
+// Если тип хотя бы одной сущности не зарегистрирован, не сохраняется ничего.
+func (m *MemoryStorage) StoreBatch(entities []any) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	targets := make([]*bucket, len(entities))
+	for i, entity := range entities {
+		b, ok := m.buckets[reflect.TypeOf(entity)]
+		if !ok {
+			return fmt.Errorf("unsupported entity type: %T", entity)
+		}
+		targets[i] = b
+	}
+
+	now := time.Now()
+	for i, entity := range entities {
+		b := targets[i]
+		b.items = append(b.items, storedItem{entity: entity, storedAt: now})
+		if b.onStore != nil {
+			b.onStore(entity)
+		}
 	}
 
 	return nil
 }
 
 func (m *MemoryStorage) GetNotifications() []*models.Notification {
-	return m.notifications
+	return Entities[*models.Notification](m)
 }
 
 func (m *MemoryStorage) GetSentNotifications() []*models.SentNotification {
-	return m.sentNotifications
-}
\ No newline at end of file
+	return Entities[*models.SentNotification](m)
+}
+
+// GetNotificationByID возвращает уведомление по его идентификатору
+func (m *MemoryStorage) GetNotificationByID(id string) (*models.Notification, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	notification, ok := m.notificationsByID[id]
+	return notification, ok
+}
+
+// GetSentNotificationsByNotificationID возвращает сообщения Telegram, отправленные
+// для уведомления (длинное уведомление может быть отправлено несколькими частями)
+func (m *MemoryStorage) GetSentNotificationsByNotificationID(id string) []*models.SentNotification {
+	var sent []*models.SentNotification
+	for _, sentNotification := range m.GetSentNotifications() {
+		if sentNotification.NotificationID == id {
+			sent = append(sent, sentNotification)
+		}
+	}
+	return sent
+}
+
+// Retention ограничивает объем хранилища при сжатии
+type Retention struct {
+	// MaxItems сколько последних сущностей каждого типа хранить (0 — без ограничения)
+	MaxItems int
+	// MaxAge сколько хранить сущность после сохранения (0 — без ограничения)
+	MaxAge time.Duration
+}
+
+// Enabled сообщает, задано ли хотя бы одно ограничение
+func (r Retention) Enabled() bool {
+	return r.MaxItems > 0 || r.MaxAge > 0
+}
+
+// Compact удаляет самые старые сущности, не укладывающиеся в retention,
+// и возвращает число удаленных
+func (m *MemoryStorage) Compact(retention Retention) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cutoff := time.Now().Add(-retention.MaxAge)
+	removed := 0
+	for _, b := range m.buckets {
+		drop := 0
+		if retention.MaxItems > 0 && len(b.items) > retention.MaxItems {
+			drop = len(b.items) - retention.MaxItems
+		}
+		if retention.MaxAge > 0 {
+			for drop < len(b.items) && b.items[drop].storedAt.Before(cutoff) {
+				drop++
+			}
+		}
+		if drop == 0 {
+			continue
+		}
+
+		for _, item := range b.items[:drop] {
+			if n, ok := item.entity.(*models.Notification); ok && m.notificationsByID[n.ID] == n {
+				delete(m.notificationsByID, n.ID)
+			}
+		}
+		// Копируем остаток, чтобы освободить память под удаленными элементами
+		b.items = append([]storedItem(nil), b.items[drop:]...)
+		b.dropped += drop
+		removed += drop
+	}
+
+	return removed
+}
+
+// RunCompactor сжимает хранилище каждые interval до отмены контекста
+func (m *MemoryStorage) RunCompactor(ctx context.Context, interval time.Duration, retention Retention) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if removed := m.Compact(retention); removed > 0 {
+				log.Printf("🧹 Из хранилища удалено устаревших записей: %d", removed)
+			}
+		}
+	}
+}
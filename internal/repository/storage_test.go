@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/mdemidenko/monitoring-platform/internal/models"
+)
+
+// failedNotification сущность, которой нет среди встроенных типов хранилища
+type failedNotification struct {
+	NotificationID string
+	Reason         string
+}
+
+func TestRegisterEntity(t *testing.T) {
+	m := NewMemoryStorage()
+
+	if err := m.Store(&failedNotification{NotificationID: "1"}); err == nil {
+		t.Fatal("unregistered type stored")
+	}
+
+	var indexed []string
+	RegisterEntity(m, func(f *failedNotification) { indexed = append(indexed, f.NotificationID) })
+
+	for _, id := range []string{"1", "2", "3"} {
+		if err := m.Store(&failedNotification{NotificationID: id, Reason: "chat not found"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	failed := Entities[*failedNotification](m)
+	if len(failed) != 3 || failed[0].NotificationID != "1" || failed[2].NotificationID != "3" || failed[1].Reason != "chat not found" {
+		t.Errorf("Entities = %+v", failed)
+	}
+	if len(indexed) != 3 {
+		t.Errorf("onStore called %d times, want 3", len(indexed))
+	}
+
+	since, next := EntitiesSince[*failedNotification](m, 2)
+	if len(since) != 1 || since[0].NotificationID != "3" || next != 3 {
+		t.Errorf("EntitiesSince(2) = %+v, %d", since, next)
+	}
+
+	// Повторная регистрация заменяет обработчик и сохраняет данные
+	RegisterEntity[*failedNotification](m)
+	if err := m.Store(&failedNotification{NotificationID: "4"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(Entities[*failedNotification](m)); got != 4 || len(indexed) != 3 {
+		t.Errorf("after re-registration: %d entities, onStore called %d times", got, len(indexed))
+	}
+
+	// Корзины типов независимы
+	notification := models.NewNotification("100", "alert")
+	if err := m.Store(notification); err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := m.GetNotificationByID(notification.ID); !ok || got != notification {
+		t.Errorf("GetNotificationByID = %v, %v", got, ok)
+	}
+	if len(m.GetNotifications()) != 1 || len(m.GetSentNotifications()) != 0 || len(Entities[*failedNotification](m)) != 4 {
+		t.Error("entity types mixed between buckets")
+	}
+	// Тип регистрируется вместе с указателем: значение другого типа не принимается
+	if err := m.Store(failedNotification{}); err == nil {
+		t.Error("value of an unregistered type stored")
+	}
+}
+
+func TestStoreBatch(t *testing.T) {
+	m := NewMemoryStorage()
+
+	notification := models.NewNotification("100", "alert")
+	sent := &models.SentNotification{MessageID: 1, NotificationID: notification.ID}
+	if err := m.StoreBatch([]any{notification, sent}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := m.GetNotificationByID(notification.ID); !ok {
+		t.Error("StoreBatch did not run the index hook")
+	}
+
+	// Неизвестный тип отклоняет весь пакет
+	if err := m.StoreBatch([]any{models.NewNotification("100", "other"), "unsupported"}); err == nil {
+		t.Fatal("expected an error for an unsupported type")
+	}
+	if got := len(m.GetNotifications()); got != 1 {
+		t.Errorf("stored %d notifications after a rejected batch, want 1", got)
+	}
+}
+
+func TestCompact(t *testing.T) {
+	tests := []struct {
+		name      string
+		retention Retention
+		// old сколько первых уведомлений сохранено час назад
+		old         int
+		wantRemoved int
+		wantFirst   string
+	}{
+		{name: "no limits", wantRemoved: 0, wantFirst: "0"},
+		{name: "max items", retention: Retention{MaxItems: 3}, wantRemoved: 7, wantFirst: "7"},
+		{name: "max age", retention: Retention{MaxAge: time.Minute}, old: 4, wantRemoved: 4, wantFirst: "4"},
+		{name: "both limits", retention: Retention{MaxItems: 8, MaxAge: time.Minute}, old: 4, wantRemoved: 4, wantFirst: "4"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMemoryStorage()
+			for i := range 10 {
+				n := models.NewNotification("100", strconv.Itoa(i))
+				n.ID = strconv.Itoa(i)
+				if err := m.Store(n); err != nil {
+					t.Fatal(err)
+				}
+			}
+			b := m.buckets[reflect.TypeFor[*models.Notification]()]
+			for i := range tt.old {
+				b.items[i].storedAt = time.Now().Add(-time.Hour)
+			}
+
+			if removed := m.Compact(tt.retention); removed != tt.wantRemoved {
+				t.Errorf("Compact removed %d, want %d", removed, tt.wantRemoved)
+			}
+
+			notifications := m.GetNotifications()
+			if len(notifications) != 10-tt.wantRemoved || notifications[0].Text != tt.wantFirst {
+				t.Errorf("left %d notifications starting with %q", len(notifications), notifications[0].Text)
+			}
+			if _, ok := m.GetNotificationByID("0"); ok != (tt.wantRemoved == 0) {
+				t.Errorf("index lookup of the oldest notification = %t", ok)
+			}
+
+			// Порядковые номера не сдвигаются: новые записи видны по старому смещению
+			if err := m.Store(models.NewNotification("100", "new")); err != nil {
+				t.Fatal(err)
+			}
+			fresh, next := EntitiesSince[*models.Notification](m, 10)
+			if len(fresh) != 1 || fresh[0].Text != "new" || next != 11 {
+				t.Errorf("EntitiesSince(10) = %d entities, next %d", len(fresh), next)
+			}
+		})
+	}
+}
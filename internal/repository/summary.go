@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/mdemidenko/monitoring-platform/internal/models"
+)
+
+// SaveSummary записывает сводку по тенантам в отдельный JSON файл
+func SaveSummary(path string, summary models.ResultSummary) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("ошибка создания файла сводки: %w", err)
+	}
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	encodeErr := encoder.Encode(summary)
+	closeErr := file.Close()
+
+	if encodeErr != nil {
+		return fmt.Errorf("ошибка записи сводки: %w", encodeErr)
+	}
+	if closeErr != nil {
+		return fmt.Errorf("ошибка закрытия файла сводки: %w", closeErr)
+	}
+
+	return nil
+}
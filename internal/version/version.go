@@ -0,0 +1,65 @@
+// Package version содержит сведения о сборке. Commit и BuildTime задаются
+// при сборке через -ldflags, например:
+//
+//	go build -ldflags "-X github.com/mdemidenko/monitoring-platform/internal/version.Commit=$(git rev-parse --short HEAD) \
+//	  -X github.com/mdemidenko/monitoring-platform/internal/version.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)" ./cmd/notifier
+package version
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+)
+
+// Значения, подставляемые при сборке через -ldflags -X
+var (
+	Commit    = ""
+	BuildTime = ""
+)
+
+// Info сведения о приложении и сборке
+type Info struct {
+	Name      string `json:"name"`
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+	GoVersion string `json:"go_version"`
+}
+
+// Get возвращает сведения о сборке. Имя и версия приложения берутся из
+// конфигурации; если Commit не задан через -ldflags, он берется из VCS
+// информации, которую go build встраивает в бинарник.
+func Get(name, appVersion string) Info {
+	info := Info{
+		Name:      name,
+		Version:   appVersion,
+		Commit:    Commit,
+		BuildTime: BuildTime,
+		GoVersion: runtime.Version(),
+	}
+
+	if buildInfo, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range buildInfo.Settings {
+			if setting.Key == "vcs.revision" && info.Commit == "" {
+				info.Commit = setting.Value
+			}
+		}
+	}
+
+	if info.Commit == "" {
+		info.Commit = "unknown"
+	}
+	if info.BuildTime == "" {
+		info.BuildTime = "unknown"
+	}
+
+	return info
+}
+
+func (i Info) String() string {
+	commit := i.Commit
+	if len(commit) > 12 {
+		commit = commit[:12]
+	}
+	return fmt.Sprintf("%s %s (commit %s, built %s, %s)", i.Name, i.Version, commit, i.BuildTime, i.GoVersion)
+}
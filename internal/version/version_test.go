@@ -0,0 +1,47 @@
+package version
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// setBuildValues подменяет значения -ldflags на время теста
+func setBuildValues(t *testing.T, commit, buildTime string) {
+	t.Helper()
+
+	oldCommit, oldBuildTime := Commit, BuildTime
+	Commit, BuildTime = commit, buildTime
+	t.Cleanup(func() { Commit, BuildTime = oldCommit, oldBuildTime })
+}
+
+func TestGetInjectedValues(t *testing.T) {
+	setBuildValues(t, "0123456789abcdef", "2026-01-01T12:00:00Z")
+
+	info := Get("notifier", "1.2.3")
+	want := Info{
+		Name:      "notifier",
+		Version:   "1.2.3",
+		Commit:    "0123456789abcdef",
+		BuildTime: "2026-01-01T12:00:00Z",
+		GoVersion: runtime.Version(),
+	}
+	if info != want {
+		t.Errorf("Get() = %+v, want %+v", info, want)
+	}
+
+	// В строке commit сокращается до 12 символов
+	if s := info.String(); !strings.Contains(s, "commit 0123456789ab,") || !strings.HasPrefix(s, "notifier 1.2.3 ") {
+		t.Errorf("String() = %q", s)
+	}
+}
+
+func TestGetDefaults(t *testing.T) {
+	setBuildValues(t, "", "")
+
+	// Тестовый бинарник собирается без VCS информации
+	info := Get("notifier", "dev")
+	if info.Commit != "unknown" || info.BuildTime != "unknown" {
+		t.Errorf("Get() = %+v, want unknown commit and build time", info)
+	}
+}